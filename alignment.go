@@ -0,0 +1,115 @@
+package gtranslate
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// newTextSpan creates the span between two byte offsets of a text
+func newTextSpan(text string, start, end int) TextSpan {
+	runeStart := utf8.RuneCountInString(text[:start])
+	return TextSpan{
+		Start:     start,
+		End:       end,
+		RuneStart: runeStart,
+		RuneEnd:   runeStart + utf8.RuneCountInString(text[start:end]),
+	}
+}
+
+// shift moves the span by the given number of bytes and runes
+func (s TextSpan) shift(bytes, runes int) TextSpan {
+	return TextSpan{
+		Start:     s.Start + bytes,
+		End:       s.End + bytes,
+		RuneStart: s.RuneStart + runes,
+		RuneEnd:   s.RuneEnd + runes,
+	}
+}
+
+// shiftSpans returns copies of the spans moved by the given number of bytes and runes
+func shiftSpans(spans []TextSpan, bytes, runes int) []TextSpan {
+	if spans == nil {
+		return nil
+	}
+	shifted := make([]TextSpan, len(spans))
+	for i, span := range spans {
+		shifted[i] = span.shift(bytes, runes)
+	}
+	return shifted
+}
+
+// utf16ToByteOffset converts an offset in UTF-16 code units, as used by Google Translate, to a byte offset of text.
+// It fails when the offset is past the end of the text or in the middle of a surrogate pair.
+func utf16ToByteOffset(text string, offset int) (int, bool) {
+	units := 0
+	for i, r := range text {
+		if units == offset {
+			return i, true
+		}
+		if units > offset {
+			return 0, false
+		}
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return len(text), units == offset
+}
+
+// aligner locates the segments of the alternate translations in the content and the translation.
+// Segments are visited in order, so each search starts where the previous segment was found.
+type aligner struct {
+	content     string
+	translation string
+
+	sentence      string // The source sentence of the last segment.
+	sentenceStart int    // The byte offset of that sentence in the content.
+	sourceFrom    int    // The byte offset from which sentences are searched in the content.
+	targetFrom    int    // The byte offset from which translations are searched in the translation.
+}
+
+// sourceSpan resolves the start and end UTF-16 offsets of a segment.
+// The offsets are taken relative to the segment's sentence first, then to the whole content,
+// and the first interpretation that falls on the segment's text is kept.
+func (a *aligner) sourceSpan(segment, sentence string, start, end int) (TextSpan, bool) {
+	if sentence != a.sentence {
+		a.sentence = sentence
+		a.sentenceStart = 0
+		if idx := strings.Index(a.content[a.sourceFrom:], sentence); sentence != "" && idx >= 0 {
+			a.sentenceStart = a.sourceFrom + idx
+			a.sourceFrom = a.sentenceStart + len(sentence)
+		}
+	}
+
+	bases := []int{a.sentenceStart, 0}
+	for _, base := range bases {
+		byteStart, isValid := utf16ToByteOffset(a.content[base:], start)
+		if !isValid {
+			continue
+		}
+		byteEnd, isValid := utf16ToByteOffset(a.content[base:], end)
+		if !isValid || byteEnd <= byteStart {
+			continue
+		}
+		if text := a.content[base+byteStart : base+byteEnd]; strings.Contains(segment, strings.TrimSpace(text)) {
+			return newTextSpan(a.content, base+byteStart, base+byteEnd), true
+		}
+	}
+	return TextSpan{}, false
+}
+
+// targetSpan finds the translation of a segment in the translation
+func (a *aligner) targetSpan(translation string) (TextSpan, bool) {
+	if translation == "" {
+		return TextSpan{}, false
+	}
+	idx := strings.Index(a.translation[a.targetFrom:], translation)
+	if idx < 0 {
+		return TextSpan{}, false
+	}
+	start := a.targetFrom + idx
+	a.targetFrom = start + len(translation)
+	return newTextSpan(a.translation, start, a.targetFrom), true
+}
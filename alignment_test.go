@@ -0,0 +1,71 @@
+package gtranslate
+
+import "testing"
+
+func TestUTF16ToByteOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		offset int
+		want   int
+		wantOK bool
+	}{
+		{"start", "a😀b", 0, 0, true},
+		{"before surrogate pair", "a😀b", 1, 1, true},
+		{"inside surrogate pair", "a😀b", 2, 0, false},
+		{"after surrogate pair", "a😀b", 3, 5, true},
+		{"end", "a😀b", 4, 6, true},
+		{"past end", "a😀b", 5, 0, false},
+		{"two surrogate pairs", "😀👍🏽x", 6, 12, true},
+		{"two-byte rune", "héllo", 2, 3, true},
+		{"three-byte runes", "日本語", 2, 6, true},
+		{"empty", "", 0, 0, true},
+		{"empty past end", "", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := utf16ToByteOffset(tt.text, tt.offset)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("got %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAlignerSourceSpan(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		segment    string
+		sentence   string
+		start, end int
+		want       TextSpan
+	}{
+		{
+			name:    "offsets after a surrogate pair",
+			content: "😀 Hello world",
+			segment: "world", sentence: "😀 Hello world",
+			start: 9, end: 14,
+			want: TextSpan{Start: 11, End: 16, RuneStart: 8, RuneEnd: 13},
+		},
+		{
+			name:    "offsets relative to the second sentence",
+			content: "Hi 👋. Good morning 🌅 friend.",
+			segment: "friend", sentence: "Good morning 🌅 friend.",
+			start: 16, end: 22,
+			want: TextSpan{Start: 27, End: 33, RuneStart: 21, RuneEnd: 27},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &aligner{content: tt.content}
+			got, ok := a.sourceSpan(tt.segment, tt.sentence, tt.start, tt.end)
+			if !ok || got != tt.want {
+				t.Fatalf("got %+v, %v, want %+v", got, ok, tt.want)
+			}
+			if text := tt.content[got.Start:got.End]; text != tt.segment {
+				t.Errorf("span covers %q, want %q", text, tt.segment)
+			}
+		})
+	}
+}
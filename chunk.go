@@ -0,0 +1,224 @@
+package gtranslate
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultMaxChunkLength is the number of characters above which Translate splits its input
+const defaultMaxChunkLength = 1800
+
+// chunkBoundaries lists the places where long text may be split, from the most to the least preferred.
+// Each expression matches the separator that stays at the end of the preceding piece.
+var chunkBoundaries = []*regexp.Regexp{
+	regexp.MustCompile(`\n[ \t\r]*\n\s*`), // paragraphs
+	regexp.MustCompile(`\n\s*`),           // lines
+	regexp.MustCompile(`[.!?…؟]+["'”’»)\]]*\s+|[。！？]+["'”’」』)]*`), // sentences
+	regexp.MustCompile(`\s+`), // words
+}
+
+// textChunk is a piece of long text sent in its own request.
+// Surrounding whitespace is kept aside, since Google does not preserve it in translations.
+type textChunk struct {
+	leading  string
+	text     string
+	trailing string
+}
+
+// WithMaxChunkLength sets the number of characters above which Translate splits its input at
+// paragraph, sentence or word boundaries and translates each chunk separately.
+// Zero disables chunking.
+func WithMaxChunkLength(length int) Option {
+	return func(c *Client) {
+		c.maxChunkLength = length
+	}
+}
+
+// splitText splits text into chunks of at most limit characters, preferring paragraph,
+// then line, sentence and word boundaries. Concatenating the chunks yields the original text.
+func splitText(text string, limit int) []textChunk {
+	pieces := splitAtBoundary(text, limit, 0)
+	chunks := make([]textChunk, len(pieces))
+	for i, piece := range pieces {
+		core := strings.TrimLeftFunc(piece, unicode.IsSpace)
+		chunks[i].leading = piece[:len(piece)-len(core)]
+		chunks[i].text = strings.TrimRightFunc(core, unicode.IsSpace)
+		chunks[i].trailing = core[len(chunks[i].text):]
+	}
+	return chunks
+}
+
+// splitAtBoundary splits text at the given boundary level and packs the pieces into segments
+// of at most limit characters, falling back to the next level for pieces that are too long
+func splitAtBoundary(text string, limit int, level int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	if level == len(chunkBoundaries) {
+		return splitByLength(text, limit)
+	}
+
+	var segments []string
+	var current strings.Builder
+	currentLength := 0
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, current.String())
+			current.Reset()
+			currentLength = 0
+		}
+	}
+
+	for _, piece := range splitAfter(text, chunkBoundaries[level]) {
+		pieceLength := utf8.RuneCountInString(piece)
+		if pieceLength > limit {
+			flush()
+			segments = append(segments, splitAtBoundary(piece, limit, level+1)...)
+			continue
+		}
+		if currentLength+pieceLength > limit {
+			flush()
+		}
+		current.WriteString(piece)
+		currentLength += pieceLength
+	}
+	flush()
+
+	return segments
+}
+
+// splitAfter splits text after each match of the separator expression
+func splitAfter(text string, separator *regexp.Regexp) []string {
+	var pieces []string
+	start := 0
+	for _, m := range separator.FindAllStringIndex(text, -1) {
+		if m[1] > start {
+			pieces = append(pieces, text[start:m[1]])
+			start = m[1]
+		}
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// splitByLength cuts text into pieces of exactly limit characters, the last one possibly shorter
+func splitByLength(text string, limit int) []string {
+	var pieces []string
+	for len(text) > 0 {
+		end, count := 0, 0
+		for end < len(text) && count < limit {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+			count++
+		}
+		pieces = append(pieces, text[:end])
+		text = text[end:]
+	}
+	return pieces
+}
+
+// Function to translate long content chunk by chunk and stitch the results back together
+func (c *Client) translateChunks(ctx context.Context, content, sourceLanguageStr, targetLanguageStr string) (*TranslationResult, error) {
+	chunks := splitText(content, c.maxChunkLength)
+	results := make([]*TranslationResult, len(chunks))
+	for i, chunk := range chunks {
+		if chunk.text == "" {
+			results[i] = &TranslationResult{}
+			continue
+		}
+		result, err := c.translateSingle(ctx, chunk.text, sourceLanguageStr, targetLanguageStr)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return mergeChunkResults(content, chunks, results), nil
+}
+
+// mergeChunkResults combines the results of each chunk into the result for the whole content,
+// restoring the whitespace that surrounded each chunk
+func mergeChunkResults(content string, chunks []textChunk, results []*TranslationResult) *TranslationResult {
+	merged := &TranslationResult{Content: content}
+	var corrected, correctedHTML string
+	anyCorrected := false
+	sourceBytes, sourceRunes := 0, 0
+	for i, result := range results {
+		chunk := chunks[i]
+
+		// Spans of the chunk are moved to the position of the chunk in the whole content and translation
+		sourceBytes += len(chunk.leading)
+		sourceRunes += utf8.RuneCountInString(chunk.leading)
+		targetBytes := len(merged.Translation) + len(chunk.leading)
+		targetRunes := utf8.RuneCountInString(merged.Translation) + utf8.RuneCountInString(chunk.leading)
+		for _, alternate := range result.AlternateTranslations {
+			alternate.SourceSpans = shiftSpans(alternate.SourceSpans, sourceBytes, sourceRunes)
+			alternate.TargetSpans = shiftSpans(alternate.TargetSpans, targetBytes, targetRunes)
+			merged.AlternateTranslations = append(merged.AlternateTranslations, alternate)
+		}
+		sourceBytes += len(chunk.text) + len(chunk.trailing)
+		sourceRunes += utf8.RuneCountInString(chunk.text) + utf8.RuneCountInString(chunk.trailing)
+
+		merged.Translation += chunk.leading + result.Translation + chunk.trailing
+		merged.SourceTransliteration += chunk.leading + result.SourceTransliteration + chunk.trailing
+		merged.TargetTransliteration += chunk.leading + result.TargetTransliteration + chunk.trailing
+
+		sentences := result.TranslatedSentences
+		if len(sentences) == 0 && (chunk.leading != "" || chunk.trailing != "") {
+			sentences = []Sentence{{}}
+		}
+		if len(sentences) > 0 {
+			first, last := &sentences[0], &sentences[len(sentences)-1]
+			first.Content = chunk.leading + first.Content
+			first.Translation = chunk.leading + first.Translation
+			last.Content += chunk.trailing
+			last.Translation += chunk.trailing
+		}
+		merged.TranslatedSentences = append(merged.TranslatedSentences, sentences...)
+
+		if merged.SourceLanguage.IsRoot() {
+			merged.SourceLanguage = result.SourceLanguage
+			merged.SourceLanguageCode = result.SourceLanguageCode
+			merged.SourceLanguageConfidence = result.SourceLanguageConfidence
+		}
+		if result.CorrectedContent != "" {
+			corrected += chunk.leading + result.CorrectedContent + chunk.trailing
+			correctedHTML += chunk.leading + result.CorrectedContentHTML + chunk.trailing
+			anyCorrected = true
+		} else {
+			corrected += chunk.leading + chunk.text + chunk.trailing
+			correctedHTML += chunk.leading + html.EscapeString(chunk.text) + chunk.trailing
+		}
+		merged.Autocorrected = merged.Autocorrected || result.Autocorrected
+		if merged.SuggestedSourceLanguageCode == "" {
+			merged.SuggestedSourceLanguage = result.SuggestedSourceLanguage
+			merged.SuggestedSourceLanguageCode = result.SuggestedSourceLanguageCode
+		}
+		merged.WordTranslations = append(merged.WordTranslations, result.WordTranslations...)
+		merged.WordSynonyms = append(merged.WordSynonyms, result.WordSynonyms...)
+		merged.WordDefinitions = append(merged.WordDefinitions, result.WordDefinitions...)
+		merged.WordExamples = append(merged.WordExamples, result.WordExamples...)
+		merged.RelatedWords = append(merged.RelatedWords, result.RelatedWords...)
+	}
+
+	// The corrected content covers the whole content, with the uncorrected chunks kept as they are
+	if anyCorrected {
+		merged.CorrectedContent = corrected
+		merged.CorrectedContentHTML = correctedHTML
+	}
+
+	// Transliterations are left empty when no chunk returned one, as for a single request
+	if strings.TrimSpace(merged.SourceTransliteration) == "" {
+		merged.SourceTransliteration = ""
+	}
+	if strings.TrimSpace(merged.TargetTransliteration) == "" {
+		merged.TargetTransliteration = ""
+	}
+	merged.Pronunciation = merged.TargetTransliteration
+	return merged
+}
@@ -0,0 +1,139 @@
+package gtranslate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []textChunk
+	}{
+		{
+			name:  "short text",
+			text:  "Hello world.",
+			limit: 100,
+			want:  []textChunk{{text: "Hello world."}},
+		},
+		{
+			name:  "paragraphs",
+			text:  "First.\n\nSecond.",
+			limit: 10,
+			want:  []textChunk{{text: "First.", trailing: "\n\n"}, {text: "Second."}},
+		},
+		{
+			name:  "sentences then words",
+			text:  "One two. Three four!",
+			limit: 10,
+			want:  []textChunk{{text: "One two.", trailing: " "}, {text: "Three", trailing: " "}, {text: "four!"}},
+		},
+		{
+			name:  "surrounding whitespace",
+			text:  "  Hi there.\n\n  Bye now.",
+			limit: 12,
+			want:  []textChunk{{leading: "  ", text: "Hi", trailing: " "}, {text: "there.", trailing: "\n\n  "}, {text: "Bye now."}},
+		},
+		{
+			name:  "sentences without spaces",
+			text:  "你好。世界。",
+			limit: 3,
+			want:  []textChunk{{text: "你好。"}, {text: "世界。"}},
+		},
+		{
+			name:  "no boundary",
+			text:  "abcdefgh",
+			limit: 3,
+			want:  []textChunk{{text: "abc"}, {text: "def"}, {text: "gh"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitText(tt.text, tt.limit)
+			if !reflect.DeepEqual(chunks, tt.want) {
+				t.Errorf("got %q, want %q", chunks, tt.want)
+			}
+
+			var joined strings.Builder
+			for _, chunk := range chunks {
+				piece := chunk.leading + chunk.text + chunk.trailing
+				if n := utf8.RuneCountInString(piece); n > tt.limit {
+					t.Errorf("chunk %q has %d characters, limit is %d", piece, n, tt.limit)
+				}
+				joined.WriteString(piece)
+			}
+			if joined.String() != tt.text {
+				t.Errorf("chunks join to %q, want %q", joined.String(), tt.text)
+			}
+		})
+	}
+}
+
+func TestTranslateChunksRestoresWhitespace(t *testing.T) {
+	// The stand-in server translates by upper-casing the query, failing on untrimmed chunks
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		q := r.Form.Get("q")
+		if strings.TrimSpace(q) != q {
+			t.Errorf("chunk %q was sent with its surrounding whitespace", q)
+		}
+		json.NewEncoder(w).Encode([]interface{}{[]interface{}{[]interface{}{strings.ToUpper(q), q, nil, nil, 10}}, nil, "en"})
+	}))
+	defer srv.Close()
+
+	text := "  First paragraph. It has two sentences!\n\n\tSecond paragraph is here,  and it is long enough to be split.\nLine two 世界。你好。 end \n"
+	for _, limit := range []int{5, 17, 30, 60, 100} {
+		client := NewClient(WithTranslateURL(srv.URL), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false), WithMaxChunkLength(limit))
+		result, err := client.Translate(context.Background(), text, language.English, language.German)
+		if err != nil {
+			t.Fatalf("limit %d: unexpected error: %v", limit, err)
+		}
+		if want := strings.ToUpper(text); result.Translation != want {
+			t.Errorf("limit %d: got translation %q, want %q", limit, result.Translation, want)
+		}
+
+		var contents, translations strings.Builder
+		for _, sentence := range result.TranslatedSentences {
+			contents.WriteString(sentence.Content)
+			translations.WriteString(sentence.Translation)
+		}
+		if contents.String() != text || translations.String() != result.Translation {
+			t.Errorf("limit %d: sentences do not add up to the content and translation", limit)
+		}
+	}
+}
+
+func TestMergeChunkResultsCorrection(t *testing.T) {
+	content := "helo wrld. Second sentence here."
+	chunks := []textChunk{{text: "helo wrld.", trailing: " "}, {text: "Second sentence here."}}
+	results := []*TranslationResult{
+		{Translation: "Hallo Welt.", CorrectedContent: "hello world.", CorrectedContentHTML: "<b><i>hello world</i></b>."},
+		{Translation: "Zweiter Satz hier."},
+	}
+
+	merged := mergeChunkResults(content, chunks, results)
+	if want := "Hallo Welt. Zweiter Satz hier."; merged.Translation != want {
+		t.Errorf("got translation %q, want %q", merged.Translation, want)
+	}
+	if want := "hello world. Second sentence here."; merged.CorrectedContent != want {
+		t.Errorf("got corrected content %q, want %q", merged.CorrectedContent, want)
+	}
+	if want := "<b><i>hello world</i></b>. Second sentence here."; merged.CorrectedContentHTML != want {
+		t.Errorf("got corrected HTML %q, want %q", merged.CorrectedContentHTML, want)
+	}
+
+	uncorrected := mergeChunkResults(content, chunks, []*TranslationResult{{}, {}})
+	if uncorrected.CorrectedContent != "" || uncorrected.CorrectedContentHTML != "" {
+		t.Errorf("got a correction %q without corrected chunks", uncorrected.CorrectedContent)
+	}
+}
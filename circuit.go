@@ -0,0 +1,223 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+// Define the states of a circuit breaker
+const (
+	CircuitClosed   CircuitState = iota // Calls go through and failures are counted.
+	CircuitOpen                         // Calls fail fast until the cool-down has elapsed.
+	CircuitHalfOpen                     // A single trial call decides whether to close or reopen the circuit.
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitOpenError is returned without sending a request while the circuit breaker of its endpoint is open.
+// It unwraps to ErrCircuitOpen.
+type CircuitOpenError struct {
+	Endpoint string    // The endpoint, or backend name, whose circuit is open.
+	RetryAt  time.Time // The time at which a trial call will be let through.
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v for %s until %s", ErrCircuitOpen, e.Endpoint, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrCircuitOpen
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// CircuitStateChange is called when the circuit breaker of an endpoint changes state
+type CircuitStateChange func(endpoint string, from, to CircuitState)
+
+// WithCircuitBreaker enables a circuit breaker per endpoint: after threshold consecutive failures
+// (rate limiting, blocking, server or network errors) requests to the endpoint fail fast with a
+// *CircuitOpenError until the cool-down has elapsed, then a single trial request decides whether to close it again
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breakers = newBreakerSet(threshold, cooldown)
+	}
+}
+
+// WithCircuitStateChange sets a callback observing the state changes of the circuit breakers.
+// The callback belongs to the client, so a client derived with With can observe the shared breakers
+// with its own callback without replacing the one of its parent.
+func WithCircuitStateChange(onStateChange CircuitStateChange) Option {
+	return func(c *Client) {
+		c.breakerStateChange = onStateChange
+	}
+}
+
+// CircuitState returns the state of the circuit breaker of the endpoint at the given URL,
+// closed when circuit breaking is disabled
+func (c *Client) CircuitState(endpoint string) CircuitState {
+	if c.breakers == nil {
+		return CircuitClosed
+	}
+	return c.breakers.get(endpoint).State()
+}
+
+// breakerSet holds the circuit breakers of the endpoints called by a client
+type breakerSet struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// newBreakerSet creates an empty set of breakers sharing the same settings
+func newBreakerSet(threshold int, cooldown time.Duration) *breakerSet {
+	return &breakerSet{
+		threshold: threshold,
+		cooldown:  cooldown,
+		breakers:  make(map[string]*circuitBreaker),
+	}
+}
+
+// get returns the breaker of the endpoint, creating it on first use
+func (s *breakerSet) get(endpoint string) *circuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breaker, ok := s.breakers[endpoint]
+	if !ok {
+		breaker = newCircuitBreaker(endpoint, s.threshold, s.cooldown)
+		s.breakers[endpoint] = breaker
+	}
+	return breaker
+}
+
+// requestEndpoint identifies the endpoint of a request by its scheme, host and path
+func requestEndpoint(req *http.Request) string {
+	return req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+}
+
+// isBreakerFailure reports whether a request failing with err counts against the health of its endpoint
+func isBreakerFailure(err error) bool {
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrBlocked) || errors.Is(err, ErrServer) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && !errors.Is(err, context.Canceled)
+}
+
+// circuitBreaker stops calling a failing endpoint after a number of consecutive failures
+// and lets a trial call through once the cool-down has elapsed
+type circuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker creates a closed breaker opening after threshold consecutive failures
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &circuitBreaker{name: name, threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may go through. It returns a *CircuitOpenError while the circuit is open,
+// or while the trial call of a half-open circuit is in flight. State changes are reported to onStateChange, if any.
+func (b *circuitBreaker) allow(onStateChange CircuitStateChange) error {
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		b.state = CircuitHalfOpen
+		b.probing = false
+	}
+
+	var err error
+	switch b.state {
+	case CircuitOpen:
+		err = &CircuitOpenError{Endpoint: b.name, RetryAt: b.openedAt.Add(b.cooldown)}
+	case CircuitHalfOpen:
+		if b.probing {
+			err = &CircuitOpenError{Endpoint: b.name, RetryAt: time.Now()}
+		}
+		b.probing = true
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(onStateChange, from, to)
+	return err
+}
+
+// record updates the breaker with the outcome of a call that was allowed through,
+// reporting state changes to onStateChange, if any
+func (b *circuitBreaker) record(success bool, onStateChange CircuitStateChange) {
+	b.mu.Lock()
+	from := b.state
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.state = CircuitClosed
+	} else {
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(onStateChange, from, to)
+}
+
+// release ends a call whose outcome says nothing about the health of the endpoint,
+// such as a cancelled context, without changing the state
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// notify calls the state change callback when the state has changed
+func (b *circuitBreaker) notify(onStateChange CircuitStateChange, from, to CircuitState) {
+	if from != to && onStateChange != nil {
+		onStateChange(b.name, from, to)
+	}
+}
+
+// State returns the current state of the breaker
+func (b *circuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
@@ -0,0 +1,187 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// stateRecorder collects the state changes reported to a CircuitStateChange callback
+type stateRecorder struct {
+	changes []string
+}
+
+func (r *stateRecorder) record(endpoint string, from, to CircuitState) {
+	r.changes = append(r.changes, fmt.Sprintf("%s->%s", from, to))
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+	var recorder stateRecorder
+	b := newCircuitBreaker("test", 2, cooldown)
+
+	step := func(success bool) {
+		t.Helper()
+		if err := b.allow(recorder.record); err != nil {
+			t.Fatalf("call refused in state %s: %v", b.State(), err)
+		}
+		b.record(success, recorder.record)
+	}
+
+	step(false)
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("got state %s after one failure, want closed", state)
+	}
+	step(false)
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("got state %s after two failures, want open", state)
+	}
+
+	var openErr *CircuitOpenError
+	if err := b.allow(recorder.record); !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got error %v while open, want a *CircuitOpenError", err)
+	}
+	if openErr.Endpoint != "test" || openErr.RetryAt.Before(time.Now()) {
+		t.Errorf("got %+v, want the endpoint and a retry time in the future", openErr)
+	}
+
+	time.Sleep(cooldown + 10*time.Millisecond)
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("got state %s after the cool-down, want half-open", state)
+	}
+	step(true)
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("got state %s after a successful trial call, want closed", state)
+	}
+
+	// A failed trial call opens the circuit again at once
+	step(false)
+	step(false)
+	time.Sleep(cooldown + 10*time.Millisecond)
+	step(false)
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("got state %s after a failed trial call, want open", state)
+	}
+
+	want := []string{
+		"closed->open", "open->half-open", "half-open->closed",
+		"closed->open", "open->half-open", "half-open->open",
+	}
+	if !reflect.DeepEqual(recorder.changes, want) {
+		t.Errorf("got state changes %v, want %v", recorder.changes, want)
+	}
+}
+
+func TestCircuitBreakerSingleTrialCall(t *testing.T) {
+	const cooldown = 10 * time.Millisecond
+	b := newCircuitBreaker("test", 1, cooldown)
+	b.allow(nil)
+	b.record(false, nil)
+	time.Sleep(cooldown + 10*time.Millisecond)
+
+	if err := b.allow(nil); err != nil {
+		t.Fatalf("trial call refused: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := b.allow(nil); !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d during the trial call: got error %v, want ErrCircuitOpen", i, err)
+		}
+	}
+
+	// Releasing the trial call frees the slot without deciding the state
+	b.release()
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("got state %s after release, want half-open", state)
+	}
+	if err := b.allow(nil); err != nil {
+		t.Fatalf("trial call refused after release: %v", err)
+	}
+	if err := b.allow(nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("got error %v during the second trial call, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerCancelledTrialCall(t *testing.T) {
+	const cooldown = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+			// The caller gives up on the trial call
+			cancel()
+			<-r.Context().Done()
+		default:
+			fmt.Fprint(w, capturedHello)
+		}
+	}))
+	defer srv.Close()
+
+	var recorder stateRecorder
+	client := NewClient(
+		WithTranslateURL(srv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithLanguageValidation(false),
+		WithCircuitBreaker(1, cooldown),
+		WithCircuitStateChange(recorder.record),
+	)
+
+	if _, err := client.Translate(context.Background(), "Hello", language.English, language.German); !errors.Is(err, ErrServer) {
+		t.Fatalf("got error %v, want ErrServer", err)
+	}
+	time.Sleep(cooldown + 10*time.Millisecond)
+	if _, err := client.Translate(ctx, "Hello", language.English, language.German); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+
+	// The cancelled trial call neither reopened the circuit nor kept the trial slot
+	if _, err := client.Translate(context.Background(), "Hello", language.English, language.German); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if !reflect.DeepEqual(recorder.changes, want) {
+		t.Errorf("got state changes %v, want %v", recorder.changes, want)
+	}
+}
+
+func TestCircuitStateChangeDerivedClient(t *testing.T) {
+	const cooldown = 10 * time.Millisecond
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	var parentChanges, derivedChanges stateRecorder
+	parent := NewClient(
+		WithTranslateURL(srv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithLanguageValidation(false),
+		WithCircuitBreaker(1, cooldown),
+		WithCircuitStateChange(parentChanges.record),
+	)
+	derived := parent.With(WithCircuitStateChange(derivedChanges.record))
+
+	parent.Translate(context.Background(), "Hello", language.English, language.German)
+	time.Sleep(cooldown + 10*time.Millisecond)
+	derived.Translate(context.Background(), "Hello", language.English, language.German)
+
+	// Both clients share the breaker, each observing the changes caused by its own calls
+	if want := []string{"closed->open"}; !reflect.DeepEqual(parentChanges.changes, want) {
+		t.Errorf("parent: got state changes %v, want %v", parentChanges.changes, want)
+	}
+	if want := []string{"open->half-open", "half-open->open"}; !reflect.DeepEqual(derivedChanges.changes, want) {
+		t.Errorf("derived: got state changes %v, want %v", derivedChanges.changes, want)
+	}
+}
@@ -0,0 +1,186 @@
+package gtranslate
+
+import (
+	"golang.org/x/text/language"
+	"net/http"
+	"time"
+)
+
+// Define defaults of a new client
+const (
+	defaultTimeout      = time.Second * 10 // The request timeout used when no custom HTTP client or timeout is given
+	defaultMaxURLLength = 2000             // The URL length above which single translations are sent as POST
+)
+
+// Client is an independently configured translator. Use NewClient to create one.
+// A Client is safe for concurrent use by multiple goroutines.
+type Client struct {
+	httpClient     *http.Client
+	translateURL   string
+	batchURL       string
+	languagesURL   string
+	providerURL    string
+	userAgent      string
+	sourceLanguage language.Tag
+	targetLanguage language.Tag
+	retryPolicy    RetryPolicy
+	rateLimiter    *RateLimiter
+	maxChunkLength int
+	maxURLLength   int
+	sections       Section
+	tkkProvider    TKKProvider
+	breakers       *breakerSet
+	languages      *languageCache
+
+	frequencyThresholds FrequencyThresholds
+
+	validateLanguages  bool
+	strictLanguages    bool
+	strictDecoding     bool
+	warningHandler     func(error)
+	breakerStateChange CircuitStateChange
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// NewClient creates a Client configured with the given options
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{
+			Timeout:       defaultTimeout,
+			CheckRedirect: stopRedirect,
+		},
+		translateURL:        googleTranslateAPI,
+		batchURL:            googleTranslateBatchAPI,
+		languagesURL:        googleTranslateLanguagesAPI,
+		userAgent:           userAgent,
+		maxChunkLength:      defaultMaxChunkLength,
+		maxURLLength:        defaultMaxURLLength,
+		sections:            SectionAll,
+		languages:           newLanguageCache(defaultLanguagesExpiry),
+		validateLanguages:   true,
+		frequencyThresholds: DefaultFrequencyThresholds,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tkkProvider == nil {
+		provider := NewWebTKKProvider(c.httpClient, webPageURL(c.translateURL), defaultTKKExpiry)
+		provider.userAgent = c.userAgent
+		c.tkkProvider = provider
+	}
+	return c
+}
+
+// With returns a copy of the client with the given options applied on top of its configuration.
+// The copy shares the HTTP client, rate limiter, circuit breakers, TKK provider and language cache
+// of c unless the options replace them, so it is cheap enough to derive for a single call.
+func (c *Client) With(opts ...Option) *Client {
+	derived := *c
+	for _, opt := range opts {
+		opt(&derived)
+	}
+	return &derived
+}
+
+// stopRedirect keeps redirects from being followed. They point to the captcha page,
+// so they are reported as ErrBlocked instead.
+func stopRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+// The client is copied, so later options such as WithTimeout do not modify the caller's value.
+// Unless the client has its own redirect policy, redirects are not followed, as with the default client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient == nil {
+			return
+		}
+		hc := *httpClient
+		if hc.CheckRedirect == nil {
+			hc.CheckRedirect = stopRedirect
+		}
+		c.httpClient = &hc
+	}
+}
+
+// WithTransport sets the RoundTripper used by the client's HTTP client
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Transport = transport
+		c.httpClient = &hc
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = timeout
+		c.httpClient = &hc
+	}
+}
+
+// WithTranslateURL sets the URL of the single translation endpoint
+func WithTranslateURL(apiURL string) Option {
+	return func(c *Client) {
+		c.translateURL = apiURL
+	}
+}
+
+// WithBatchURL sets the URL of the batch translation endpoint
+func WithBatchURL(apiURL string) Option {
+	return func(c *Client) {
+		c.batchURL = apiURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithSourceLanguage sets the source language used when a call does not specify one.
+// The root tag means automatic detection.
+func WithSourceLanguage(tag language.Tag) Option {
+	return func(c *Client) {
+		c.sourceLanguage = tag
+	}
+}
+
+// WithTargetLanguage sets the target language used when a call does not specify one.
+// Without it, calls that do not specify a target language fail with ErrNoTargetLanguage.
+func WithTargetLanguage(tag language.Tag) Option {
+	return func(c *Client) {
+		c.targetLanguage = tag
+	}
+}
+
+// resolveLanguages replaces root source and target languages with the client's defaults.
+// It fails with ErrNoTargetLanguage when no target language is available.
+func (c *Client) resolveLanguages(sourceLanguage, targetLanguage language.Tag) (language.Tag, language.Tag, error) {
+	if sourceLanguage.IsRoot() {
+		sourceLanguage = c.sourceLanguage
+	}
+	if targetLanguage.IsRoot() {
+		targetLanguage = c.targetLanguage
+	}
+	if targetLanguage.IsRoot() {
+		return sourceLanguage, targetLanguage, ErrNoTargetLanguage
+	}
+	return sourceLanguage, targetLanguage, nil
+}
+
+// WithMaxURLLength sets the URL length above which the single translation endpoint is called
+// with POST, sending the query in the request body. Zero sends every request as POST and
+// a negative length always uses GET.
+func WithMaxURLLength(length int) Option {
+	return func(c *Client) {
+		c.maxURLLength = length
+	}
+}
@@ -0,0 +1,310 @@
+package gtranslate
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/text/language"
+	"net/http"
+	"net/url"
+)
+
+// cloudTranslationAPI is the endpoint of the official Cloud Translation API
+const cloudTranslationAPI = "https://translation.googleapis.com"
+
+// CloudV2Translator translates with the Cloud Translation API v2 (Basic), authenticated with an API key
+type CloudV2Translator struct {
+	client  *Client
+	apiKey  string
+	baseURL string
+}
+
+// Make sure the Cloud Translation backends implement the interface
+var (
+	_ Translator = (*CloudV2Translator)(nil)
+	_ Translator = (*CloudV3Translator)(nil)
+)
+
+// NewCloudV2Translator creates a Cloud Translation v2 backend authenticated with the API key
+func NewCloudV2Translator(apiKey string, opts ...Option) *CloudV2Translator {
+	client, baseURL := newBackendClient(cloudTranslationAPI, opts)
+	return &CloudV2Translator{
+		client:  client,
+		apiKey:  apiKey,
+		baseURL: baseURL + "/language/translate/v2",
+	}
+}
+
+// Name returns the identifier of the Cloud Translation v2 backend
+func (t *CloudV2Translator) Name() string {
+	return "google-cloud-v2"
+}
+
+// Translate translates a single piece of content as a batch of one item
+func (t *CloudV2Translator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	return translateAsBatch(ctx, t, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch translates several pieces of content in a single request
+func (t *CloudV2Translator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	sourceLanguage, targetLanguage, err := t.client.resolveLanguages(sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	target, err := t.client.languageCode(targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	request := map[string]interface{}{
+		"q":      contents,
+		"target": target,
+		"format": "text",
+	}
+	if !sourceLanguage.IsRoot() {
+		if request["source"], err = t.client.languageCode(sourceLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	var response struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText         string `json:"translatedText"`
+				DetectedSourceLanguage string `json:"detectedSourceLanguage"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	err = t.client.doJSON(ctx, "POST", t.endpoint("", nil), nil, request, &response, countCharacters(contents))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data.Translations) != len(contents) {
+		return nil, fmt.Errorf("%w: expected %d translations, got %d", ErrMalformedResponse, len(contents), len(response.Data.Translations))
+	}
+
+	results := make([]BatchResult, len(contents))
+	for i, translation := range response.Data.Translations {
+		results[i] = BatchResult{
+			Index:          i,
+			Content:        contents[i],
+			Translation:    translation.TranslatedText,
+			SourceLanguage: sourceLanguage,
+		}
+		if translation.DetectedSourceLanguage != "" {
+			if results[i].SourceLanguage, err = t.client.languageTag(translation.DetectedSourceLanguage); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// Detect detects the language of the content
+func (t *CloudV2Translator) Detect(ctx context.Context, content string) (*Detection, error) {
+	var response struct {
+		Data struct {
+			Detections [][]struct {
+				Language   string  `json:"language"`
+				Confidence float64 `json:"confidence"`
+			} `json:"detections"`
+		} `json:"data"`
+	}
+	err := t.client.doJSON(ctx, "POST", t.endpoint("/detect", nil), nil, map[string]interface{}{"q": content}, &response, countCharacters([]string{content}))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data.Detections) == 0 || len(response.Data.Detections[0]) == 0 {
+		return nil, fmt.Errorf("%w: no detected language", ErrMalformedResponse)
+	}
+
+	detection := response.Data.Detections[0][0]
+	return &Detection{Language: googleTag(detection.Language), Confidence: detection.Confidence}, nil
+}
+
+// SupportedLanguages lists the languages supported by the API, named in the display language.
+// The source and target lists are the same.
+func (t *CloudV2Translator) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	parameters := url.Values{}
+	if !displayLanguage.IsRoot() {
+		target, err := t.client.languageCode(displayLanguage)
+		if err != nil {
+			return nil, err
+		}
+		parameters.Set("target", target)
+	}
+
+	var response struct {
+		Data struct {
+			Languages []struct {
+				Language string `json:"language"`
+				Name     string `json:"name"`
+			} `json:"languages"`
+		} `json:"data"`
+	}
+	err := t.client.doJSON(ctx, "GET", t.endpoint("/languages", parameters), nil, nil, &response, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]Language, len(response.Data.Languages))
+	for i, l := range response.Data.Languages {
+		languages[i] = Language{Tag: googleTag(l.Language), Code: l.Language, Name: l.Name}
+	}
+	return &Languages{Source: languages, Target: languages}, nil
+}
+
+// endpoint returns the URL of an API method, authenticated with the API key
+func (t *CloudV2Translator) endpoint(path string, parameters url.Values) string {
+	if parameters == nil {
+		parameters = url.Values{}
+	}
+	parameters.Set("key", t.apiKey)
+	return t.baseURL + path + "?" + parameters.Encode()
+}
+
+// CloudV3Translator translates with the Cloud Translation API v3 (Advanced) of a Google Cloud project
+type CloudV3Translator struct {
+	client      *Client
+	accessToken string
+	baseURL     string
+}
+
+// NewCloudV3Translator creates a Cloud Translation v3 backend for the project, using the global location.
+// The OAuth2 access token is sent as a bearer token; leave it empty when the HTTP client set with
+// WithHTTPClient or WithTransport already authenticates requests.
+func NewCloudV3Translator(projectID, accessToken string, opts ...Option) *CloudV3Translator {
+	client, baseURL := newBackendClient(cloudTranslationAPI, opts)
+	return &CloudV3Translator{
+		client:      client,
+		accessToken: accessToken,
+		baseURL:     baseURL + "/v3/projects/" + url.PathEscape(projectID) + "/locations/global",
+	}
+}
+
+// Name returns the identifier of the Cloud Translation v3 backend
+func (t *CloudV3Translator) Name() string {
+	return "google-cloud-v3"
+}
+
+// Translate translates a single piece of content as a batch of one item
+func (t *CloudV3Translator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	return translateAsBatch(ctx, t, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch translates several pieces of content in a single request
+func (t *CloudV3Translator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	sourceLanguage, targetLanguage, err := t.client.resolveLanguages(sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	target, err := t.client.languageCode(targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	request := map[string]interface{}{
+		"contents":           contents,
+		"mimeType":           "text/plain",
+		"targetLanguageCode": target,
+	}
+	if !sourceLanguage.IsRoot() {
+		if request["sourceLanguageCode"], err = t.client.languageCode(sourceLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	var response struct {
+		Translations []struct {
+			TranslatedText       string `json:"translatedText"`
+			DetectedLanguageCode string `json:"detectedLanguageCode"`
+		} `json:"translations"`
+	}
+	err = t.client.doJSON(ctx, "POST", t.baseURL+":translateText", t.header(), request, &response, countCharacters(contents))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Translations) != len(contents) {
+		return nil, fmt.Errorf("%w: expected %d translations, got %d", ErrMalformedResponse, len(contents), len(response.Translations))
+	}
+
+	results := make([]BatchResult, len(contents))
+	for i, translation := range response.Translations {
+		results[i] = BatchResult{Index: i, Content: contents[i], Translation: translation.TranslatedText, SourceLanguage: sourceLanguage}
+		if translation.DetectedLanguageCode != "" {
+			if results[i].SourceLanguage, err = t.client.languageTag(translation.DetectedLanguageCode); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// Detect detects the language of the content
+func (t *CloudV3Translator) Detect(ctx context.Context, content string) (*Detection, error) {
+	request := map[string]interface{}{"content": content, "mimeType": "text/plain"}
+
+	var response struct {
+		Languages []struct {
+			LanguageCode string  `json:"languageCode"`
+			Confidence   float64 `json:"confidence"`
+		} `json:"languages"`
+	}
+	err := t.client.doJSON(ctx, "POST", t.baseURL+":detectLanguage", t.header(), request, &response, countCharacters([]string{content}))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Languages) == 0 {
+		return nil, fmt.Errorf("%w: no detected language", ErrMalformedResponse)
+	}
+
+	detection := response.Languages[0]
+	return &Detection{Language: googleTag(detection.LanguageCode), Confidence: detection.Confidence}, nil
+}
+
+// SupportedLanguages lists the source and target languages supported by the API, named in the display language
+func (t *CloudV3Translator) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	endpoint := t.baseURL + "/supportedLanguages"
+	if !displayLanguage.IsRoot() {
+		display, err := t.client.languageCode(displayLanguage)
+		if err != nil {
+			return nil, err
+		}
+		endpoint += "?" + url.Values{"displayLanguageCode": {display}}.Encode()
+	}
+
+	var response struct {
+		Languages []struct {
+			LanguageCode  string `json:"languageCode"`
+			DisplayName   string `json:"displayName"`
+			SupportSource bool   `json:"supportSource"`
+			SupportTarget bool   `json:"supportTarget"`
+		} `json:"languages"`
+	}
+	err := t.client.doJSON(ctx, "GET", endpoint, t.header(), nil, &response, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := &Languages{}
+	for _, l := range response.Languages {
+		item := Language{Tag: googleTag(l.LanguageCode), Code: l.LanguageCode, Name: l.DisplayName}
+		if l.SupportSource {
+			languages.Source = append(languages.Source, item)
+		}
+		if l.SupportTarget {
+			languages.Target = append(languages.Target, item)
+		}
+	}
+	return languages, nil
+}
+
+// header returns the authentication header of Cloud Translation v3 requests
+func (t *CloudV3Translator) header() http.Header {
+	if t.accessToken == "" {
+		return nil
+	}
+	return http.Header{"Authorization": {"Bearer " + t.accessToken}}
+}
+
+// googleTag returns the tag of a Google language code, parsing it as a BCP 47 tag when it is not a known code
+func googleTag(code string) language.Tag {
+	return listedTag(GoogleLanguageCodec, code)
+}
@@ -0,0 +1,203 @@
+package gtranslate
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/text/language"
+	"strings"
+)
+
+// ErrUnmappedLanguage is returned when a language has no equivalent in the code set of a provider
+var ErrUnmappedLanguage = errors.New("unmapped language")
+
+// UnmappedLanguageError reports a language tag or provider code that could not be mapped.
+// It unwraps to ErrUnmappedLanguage.
+type UnmappedLanguageError struct {
+	Provider string       // The name of the provider whose code set was used.
+	Tag      language.Tag // The tag that could not be mapped to a code, if mapping a tag.
+	Code     string       // The code that could not be mapped to a tag, if mapping a code.
+}
+
+// Error implements the error interface
+func (e *UnmappedLanguageError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%v: %s code %q", ErrUnmappedLanguage, e.Provider, e.Code)
+	}
+	return fmt.Sprintf("%v: no %s code for %s", ErrUnmappedLanguage, e.Provider, e.Tag)
+}
+
+// Unwrap returns ErrUnmappedLanguage
+func (e *UnmappedLanguageError) Unwrap() error {
+	return ErrUnmappedLanguage
+}
+
+// LanguageCodec maps BCP 47 language tags to the language codes of a provider and back.
+// Tags are matched to the closest provider language, so that en-US maps to en and zh-Hans-CN to zh-CN.
+type LanguageCodec struct {
+	provider string
+	codes    []string
+	tags     []language.Tag
+	aliases  map[string]string
+	matcher  language.Matcher
+}
+
+// NewLanguageCodec creates a codec for the given provider codes. Aliases map tags the matcher
+// would not resolve, given in their canonical string form, directly to a provider code.
+func NewLanguageCodec(provider string, codes []string, aliases map[string]string) *LanguageCodec {
+	lc := &LanguageCodec{provider: provider, aliases: aliases}
+	for _, code := range codes {
+		tag, err := language.Parse(code)
+		if err != nil {
+			continue
+		}
+		lc.codes = append(lc.codes, code)
+		lc.tags = append(lc.tags, tag)
+	}
+	lc.matcher = language.NewMatcher(lc.tags)
+	return lc
+}
+
+// Code returns the provider code for a tag. When the tag has no close equivalent, the base language
+// is returned together with an *UnmappedLanguageError.
+func (lc *LanguageCodec) Code(tag language.Tag) (string, error) {
+	base, _ := tag.Base()
+	script, scriptConfidence := tag.Script()
+	candidates := []string{tag.String(), base.String()}
+	if scriptConfidence == language.Exact {
+		candidates = []string{tag.String(), base.String() + "-" + script.String()}
+	}
+	for _, candidate := range candidates {
+		if code, ok := lc.aliases[candidate]; ok {
+			return code, nil
+		}
+	}
+
+	// The matcher also falls back to languages users of the tag commonly understand, such as
+	// English for Klingon, which are not translations into the requested language
+	_, index, confidence := lc.matcher.Match(tag)
+	if matchedBase, _ := lc.tags[index].Base(); confidence < language.High || matchedBase != base {
+		return base.String(), &UnmappedLanguageError{Provider: lc.provider, Tag: tag}
+	}
+	return lc.codes[index], nil
+}
+
+// Tag returns the tag for a provider code. Codes outside the provider's code set are accepted
+// when they are BCP 47 tags of a provider language, such as he for iw. Otherwise the code is parsed
+// as a BCP 47 tag and returned together with an *UnmappedLanguageError.
+func (lc *LanguageCodec) Tag(code string) (language.Tag, error) {
+	for i, c := range lc.codes {
+		if strings.EqualFold(c, code) {
+			return lc.tags[i], nil
+		}
+	}
+
+	tag, err := language.Parse(code)
+	if err == nil {
+		if _, _, confidence := lc.matcher.Match(tag); confidence == language.Exact {
+			return tag, nil
+		}
+	}
+	return tag, &UnmappedLanguageError{Provider: lc.provider, Code: code}
+}
+
+// GoogleLanguageCodec maps tags to the codes of Google Translate, which still uses some deprecated
+// ISO 639 codes (iw, jw, tl) and distinguishes a few regional or script variants
+var GoogleLanguageCodec = NewLanguageCodec("google", []string{
+	"af", "ak", "am", "ar", "as", "ay", "az", "be", "bg", "bho", "bm", "bn", "bs", "ca", "ceb", "ckb",
+	"co", "cs", "cy", "da", "de", "doi", "dv", "ee", "el", "en", "eo", "es", "et", "eu", "fa", "fa-AF",
+	"fi", "fr", "fy", "ga", "gd", "gl", "gn", "gom", "gu", "ha", "haw", "hi", "hmn", "hr", "ht", "hu",
+	"hy", "id", "ig", "ilo", "is", "it", "iw", "ja", "jw", "ka", "kk", "km", "kn", "ko", "kri", "ku",
+	"ky", "la", "lb", "lg", "ln", "lo", "lt", "lus", "lv", "mai", "mg", "mi", "mk", "ml", "mn",
+	"mni-Mtei", "mr", "ms", "mt", "my", "ne", "nl", "no", "nso", "ny", "om", "or", "pa", "pa-Arab",
+	"pl", "ps", "pt", "pt-PT", "qu", "ro", "ru", "rw", "sa", "sd", "si", "sk", "sl", "sm", "sn", "so",
+	"sq", "sr", "st", "su", "sv", "sw", "ta", "te", "tg", "th", "ti", "tk", "tl", "tr", "ts", "tt",
+	"ug", "uk", "ur", "uz", "vi", "xh", "yi", "yo", "zh-CN", "zh-TW", "zu",
+}, map[string]string{
+	// Google only offers Meitei in Meitei Mayek script. Serbian is only offered in Cyrillic script,
+	// so Serbian in Latin script is left unmapped rather than sent as sr.
+	"mni": "mni-Mtei",
+	// Both written forms of Norwegian are served by the macrolanguage
+	"nb": "no",
+	"nn": "no",
+})
+
+// WithStrictLanguages makes unmapped languages fail requests with an *UnmappedLanguageError.
+// By default they are reported to the warning handler and a best-effort code or tag is used.
+func WithStrictLanguages(strict bool) Option {
+	return func(c *Client) {
+		c.strictLanguages = strict
+	}
+}
+
+// WithWarningHandler sets a function receiving non-fatal problems, such as unmapped languages in lenient mode
+func WithWarningHandler(handler func(error)) Option {
+	return func(c *Client) {
+		c.warningHandler = handler
+	}
+}
+
+// warn reports a non-fatal problem to the warning handler
+func (c *Client) warn(err error) {
+	if c.warningHandler != nil {
+		c.warningHandler(err)
+	}
+}
+
+// languageCode returns the Google code for a request language, "auto" for the root tag
+func (c *Client) languageCode(tag language.Tag) (string, error) {
+	if tag.IsRoot() {
+		return "auto", nil
+	}
+	return c.codecCode(GoogleLanguageCodec, tag)
+}
+
+// languageTag returns the tag for a language code found in a Google response.
+// An empty code yields the root tag.
+func (c *Client) languageTag(code string) (language.Tag, error) {
+	return c.codecTag(GoogleLanguageCodec, code)
+}
+
+// requestLanguageTag returns the tag for a Google code given by the caller.
+// An empty code and "auto" yield the root tag.
+func (c *Client) requestLanguageTag(code string) (language.Tag, error) {
+	if code == "auto" {
+		return language.Und, nil
+	}
+	return c.languageTag(code)
+}
+
+// codecCode returns the code of a request language in the code set of a provider.
+// Unmapped languages fail in strict mode and are reported to the warning handler otherwise.
+func (c *Client) codecCode(codec *LanguageCodec, tag language.Tag) (string, error) {
+	code, err := codec.Code(tag)
+	if err != nil {
+		if c.strictLanguages {
+			return "", err
+		}
+		c.warn(err)
+	}
+	return code, nil
+}
+
+// codecTag returns the tag for a language code found in the response of a provider.
+// An empty code yields the root tag.
+func (c *Client) codecTag(codec *LanguageCodec, code string) (language.Tag, error) {
+	if code == "" {
+		return language.Und, nil
+	}
+	tag, err := codec.Tag(code)
+	if err != nil {
+		if c.strictLanguages {
+			return tag, err
+		}
+		c.warn(err)
+	}
+	return tag, nil
+}
+
+// listedTag returns the tag for a code listed by a provider, parsing it as a BCP 47 tag when it is
+// not in the code set. Providers may list languages added after the codec, so these are not reported.
+func listedTag(codec *LanguageCodec, code string) language.Tag {
+	tag, _ := codec.Tag(code)
+	return tag
+}
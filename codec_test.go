@@ -0,0 +1,128 @@
+package gtranslate
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLanguageCodecCode(t *testing.T) {
+	tests := []struct {
+		codec        *LanguageCodec
+		tag          string
+		want         string
+		wantUnmapped bool
+	}{
+		{GoogleLanguageCodec, "en-US", "en", false},
+		{GoogleLanguageCodec, "zh-Hans-CN", "zh-CN", false},
+		{GoogleLanguageCodec, "zh-Hant", "zh-TW", false},
+		{GoogleLanguageCodec, "nb", "no", false},
+		{GoogleLanguageCodec, "he", "iw", false},
+		{GoogleLanguageCodec, "sr-Latn", "sr", true},
+
+		{MicrosoftLanguageCodec, "en-US", "en", false},
+		{MicrosoftLanguageCodec, "zh-Hans-CN", "zh-Hans", false},
+		{MicrosoftLanguageCodec, "zh-Hant", "zh-Hant", false},
+		{MicrosoftLanguageCodec, "zh-TW", "zh-Hant", false},
+		{MicrosoftLanguageCodec, "nb", "nb", false},
+		{MicrosoftLanguageCodec, "no", "nb", false},
+		{MicrosoftLanguageCodec, "sr-Latn", "sr-Latn", false},
+		{MicrosoftLanguageCodec, "pt-BR", "pt", false},
+		{MicrosoftLanguageCodec, "pt-PT", "pt-PT", false},
+		{MicrosoftLanguageCodec, "fa-AF", "prs", false},
+		{MicrosoftLanguageCodec, "tl", "fil", false},
+		{MicrosoftLanguageCodec, "eo", "eo", true},
+
+		{LibreTranslateLanguageCodec, "en-US", "en", false},
+		{LibreTranslateLanguageCodec, "zh-Hans-CN", "zh-Hans", false},
+		{LibreTranslateLanguageCodec, "zh-Hant", "zh-Hant", false},
+		{LibreTranslateLanguageCodec, "zh-TW", "zh-Hant", false},
+		{LibreTranslateLanguageCodec, "nb", "nb", false},
+		{LibreTranslateLanguageCodec, "pt-BR", "pt-BR", false},
+		{LibreTranslateLanguageCodec, "iw", "he", false},
+		{LibreTranslateLanguageCodec, "sw", "sw", true},
+
+		{DeepLTargetLanguageCodec, "en", "EN-US", false},
+		{DeepLTargetLanguageCodec, "en-US", "EN-US", false},
+		{DeepLTargetLanguageCodec, "en-GB", "EN-GB", false},
+		{DeepLTargetLanguageCodec, "pt", "PT-BR", false},
+		{DeepLTargetLanguageCodec, "pt-PT", "PT-PT", false},
+		{DeepLTargetLanguageCodec, "zh-Hans-CN", "ZH-HANS", false},
+		{DeepLTargetLanguageCodec, "zh-Hant", "ZH-HANT", false},
+		{DeepLTargetLanguageCodec, "nb", "NB", false},
+		{DeepLTargetLanguageCodec, "hi", "hi", true},
+
+		{DeepLSourceLanguageCodec, "en-US", "EN", false},
+		{DeepLSourceLanguageCodec, "pt-PT", "PT", false},
+		{DeepLSourceLanguageCodec, "zh-Hans-CN", "ZH", false},
+		{DeepLSourceLanguageCodec, "zh-Hant", "ZH", false},
+		{DeepLSourceLanguageCodec, "zh-TW", "ZH", false},
+		{DeepLSourceLanguageCodec, "no", "NB", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.codec.provider+"/"+tt.tag, func(t *testing.T) {
+			got, err := tt.codec.Code(language.MustParse(tt.tag))
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			var unmapped *UnmappedLanguageError
+			if gotUnmapped := errors.As(err, &unmapped); gotUnmapped != tt.wantUnmapped {
+				t.Errorf("got error %v, want an *UnmappedLanguageError: %v", err, tt.wantUnmapped)
+			} else if gotUnmapped && unmapped.Provider != tt.codec.provider {
+				t.Errorf("got provider %q, want %q", unmapped.Provider, tt.codec.provider)
+			}
+		})
+	}
+}
+
+func TestLanguageCodecTag(t *testing.T) {
+	tests := []struct {
+		codec        *LanguageCodec
+		code         string
+		want         string
+		wantUnmapped bool
+	}{
+		{GoogleLanguageCodec, "iw", "he", false},
+		{GoogleLanguageCodec, "zh-CN", "zh-CN", false},
+		{GoogleLanguageCodec, "xx-unknown", "und", true},
+		{MicrosoftLanguageCodec, "zh-Hans", "zh-Hans", false},
+		{MicrosoftLanguageCodec, "prs", "prs", false},
+		{LibreTranslateLanguageCodec, "zh-Hant", "zh-Hant", false},
+		{DeepLSourceLanguageCodec, "EN", "en", false},
+		{DeepLTargetLanguageCodec, "EN-GB", "en-GB", false},
+		{DeepLTargetLanguageCodec, "zh-hant", "zh-Hant", false},
+		{DeepLTargetLanguageCodec, "HI", "hi", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.codec.provider+"/"+tt.code, func(t *testing.T) {
+			got, err := tt.codec.Tag(tt.code)
+			if got.String() != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if gotUnmapped := errors.Is(err, ErrUnmappedLanguage); gotUnmapped != tt.wantUnmapped {
+				t.Errorf("got error %v, want ErrUnmappedLanguage: %v", err, tt.wantUnmapped)
+			}
+		})
+	}
+}
+
+func TestCodecCodeStrictness(t *testing.T) {
+	var warnings []error
+	lenient := NewClient(WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	code, err := lenient.codecCode(MicrosoftLanguageCodec, language.Make("eo"))
+	if err != nil || code != "eo" {
+		t.Errorf("lenient: got %q, %v, want the base language without an error", code, err)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrUnmappedLanguage) {
+		t.Errorf("lenient: got warnings %v, want one unmapped language", warnings)
+	}
+
+	strict := lenient.With(WithStrictLanguages(true))
+	if _, err := strict.codecCode(MicrosoftLanguageCodec, language.Make("eo")); !errors.Is(err, ErrUnmappedLanguage) {
+		t.Errorf("strict: got error %v, want ErrUnmappedLanguage", err)
+	}
+	if tag, err := strict.codecTag(MicrosoftLanguageCodec, ""); err != nil || !tag.IsRoot() {
+		t.Errorf("strict: got %s, %v for an empty code, want the root tag", tag, err)
+	}
+}
@@ -0,0 +1,288 @@
+package gtranslate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// DecodeProblem is the kind of mismatch between a response and its expected layout
+type DecodeProblem int
+
+// Define the kinds of decoding problems
+const (
+	ProblemUnknownPosition  DecodeProblem = iota // A position holds a value the decoder does not know about.
+	ProblemMistypedPosition                      // A known position holds a value of an unexpected JSON type.
+)
+
+// String returns the name of the problem
+func (p DecodeProblem) String() string {
+	switch p {
+	case ProblemUnknownPosition:
+		return "unknown position"
+	case ProblemMistypedPosition:
+		return "mistyped position"
+	default:
+		return "unknown problem"
+	}
+}
+
+// DecodeDiagnostic describes a part of a response that does not match the layout expected by the decoder
+type DecodeDiagnostic struct {
+	Path     string        // The position in the response, such as [1][0][2].
+	Problem  DecodeProblem // The kind of mismatch.
+	Expected string        // The expected JSON type, empty for unknown positions.
+	Found    string        // The JSON type found at the position.
+}
+
+// String describes the diagnostic
+func (d DecodeDiagnostic) String() string {
+	if d.Problem == ProblemMistypedPosition {
+		return fmt.Sprintf("%s at %s: expected %s, found %s", d.Problem, d.Path, d.Expected, d.Found)
+	}
+	return fmt.Sprintf("%s at %s: found %s", d.Problem, d.Path, d.Found)
+}
+
+// SchemaError reports that a response does not match the expected layout, which usually means
+// the provider changed its format. It is returned in strict decoding mode and unwraps to ErrMalformedResponse.
+type SchemaError struct {
+	Diagnostics []DecodeDiagnostic // The mismatches found in the response.
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	if len(e.Diagnostics) == 1 {
+		return fmt.Sprintf("%v: %s", ErrMalformedResponse, e.Diagnostics[0])
+	}
+	return fmt.Sprintf("%v: %s and %d more", ErrMalformedResponse, e.Diagnostics[0], len(e.Diagnostics)-1)
+}
+
+// Unwrap returns ErrMalformedResponse
+func (e *SchemaError) Unwrap() error {
+	return ErrMalformedResponse
+}
+
+// WithStrictDecoding makes responses that do not match the expected layout fail with a *SchemaError.
+// In the default lenient mode, unexpected values are skipped and the diagnostics are passed to the
+// warning handler, if any, as a *SchemaError.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *Client) {
+		c.strictDecoding = strict
+	}
+}
+
+// checkSchema fails with a *SchemaError in strict decoding mode and warns otherwise
+func (c *Client) checkSchema(diagnostics []DecodeDiagnostic) error {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	err := &SchemaError{Diagnostics: diagnostics}
+	if c.strictDecoding {
+		return err
+	}
+	c.warn(err)
+	return nil
+}
+
+// decoded records the diagnostics of a value decoded from a positional array
+type decoded struct {
+	diagnostics []DecodeDiagnostic
+}
+
+// decodeDiagnostics returns the diagnostics recorded while decoding the value
+func (d *decoded) decodeDiagnostics() []DecodeDiagnostic {
+	return d.diagnostics
+}
+
+// diagnosable is implemented by values that record diagnostics while decoding
+type diagnosable interface {
+	decodeDiagnostics() []DecodeDiagnostic
+}
+
+// positions decodes the items of a JSON array by position, recording the diagnostics of
+// mistyped items and, once finished, of the items that were never read
+type positions struct {
+	path        string
+	items       []json.RawMessage
+	used        []bool
+	diagnostics *[]DecodeDiagnostic
+}
+
+// newPositions splits a JSON array into its items. It fails if the data is not an array.
+func newPositions(data []byte) (*positions, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return &positions{items: items, used: make([]bool, len(items)), diagnostics: &[]DecodeDiagnostic{}}, nil
+}
+
+// len returns the number of items
+func (p *positions) len() int {
+	return len(p.items)
+}
+
+// raw returns the item at the given index and marks it as read. It reports false for missing and null items.
+func (p *positions) raw(i int) (json.RawMessage, bool) {
+	if i >= len(p.items) {
+		return nil, false
+	}
+	p.used[i] = true
+	if isNull(p.items[i]) {
+		return nil, false
+	}
+	return p.items[i], true
+}
+
+// ignore marks items whose meaning is known but that are not used
+func (p *positions) ignore(indexes ...int) {
+	for _, i := range indexes {
+		if i < len(p.items) {
+			p.used[i] = true
+		}
+	}
+}
+
+// decode decodes the item at the given index into v, recording a diagnostic if it has another type.
+// It reports whether a value was decoded.
+func (p *positions) decode(i int, v interface{}, expected string) bool {
+	raw, isPresent := p.raw(i)
+	if !isPresent {
+		return false
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		p.add(DecodeDiagnostic{Path: p.path + index(i), Problem: ProblemMistypedPosition, Expected: expected, Found: jsonType(raw)})
+		return false
+	}
+	if d, isDiagnosable := v.(diagnosable); isDiagnosable {
+		for _, diagnostic := range d.decodeDiagnostics() {
+			diagnostic.Path = p.path + index(i) + diagnostic.Path
+			p.add(diagnostic)
+		}
+	}
+	return true
+}
+
+// string decodes the string at the given index
+func (p *positions) string(i int) string {
+	var s string
+	p.decode(i, &s, "string")
+	return s
+}
+
+// number decodes the number at the given index
+func (p *positions) number(i int) float64 {
+	var f float64
+	p.decode(i, &f, "number")
+	return f
+}
+
+// boolean decodes the boolean at the given index
+func (p *positions) boolean(i int) bool {
+	var b bool
+	p.decode(i, &b, "boolean")
+	return b
+}
+
+// list returns the positions of the array at the given index, empty if it is missing or not an array.
+// The caller finishes the returned positions once it has read them, sharing the diagnostics of p.
+func (p *positions) list(i int) *positions {
+	empty := &positions{path: p.path + index(i), diagnostics: p.diagnostics}
+	raw, isPresent := p.raw(i)
+	if !isPresent {
+		return empty
+	}
+	nested, err := newPositions(raw)
+	if err != nil {
+		p.add(DecodeDiagnostic{Path: p.path + index(i), Problem: ProblemMistypedPosition, Expected: "array", Found: jsonType(raw)})
+		return empty
+	}
+	nested.path = p.path + index(i)
+	nested.diagnostics = p.diagnostics
+	return nested
+}
+
+// each calls fn for every item of the array at the given index, with the positions of the array
+func (p *positions) each(i int, fn func(l *positions, j int)) {
+	l := p.list(i)
+	for j := 0; j < l.len(); j++ {
+		fn(l, j)
+	}
+	l.finish()
+}
+
+// strings decodes the array of strings at the given index, skipping items of other types
+func (p *positions) strings(i int) []string {
+	l := p.list(i)
+	var values []string
+	for j := 0; j < l.len(); j++ {
+		var s string
+		if l.decode(j, &s, "string") {
+			values = append(values, s)
+		}
+	}
+	l.finish()
+	return values
+}
+
+// numbers decodes the array of numbers at the given index, skipping items of other types
+func (p *positions) numbers(i int) []float64 {
+	l := p.list(i)
+	var values []float64
+	for j := 0; j < l.len(); j++ {
+		var f float64
+		if l.decode(j, &f, "number") {
+			values = append(values, f)
+		}
+	}
+	l.finish()
+	return values
+}
+
+// add records a diagnostic
+func (p *positions) add(diagnostic DecodeDiagnostic) {
+	*p.diagnostics = append(*p.diagnostics, diagnostic)
+}
+
+// finish records the items that were never read as unknown and returns all diagnostics
+func (p *positions) finish() []DecodeDiagnostic {
+	for i, raw := range p.items {
+		if !p.used[i] && !isNull(raw) {
+			p.add(DecodeDiagnostic{Path: p.path + index(i), Problem: ProblemUnknownPosition, Found: jsonType(raw)})
+		}
+	}
+	return *p.diagnostics
+}
+
+// index formats an array index as a path element
+func index(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// isNull reports whether a JSON value is null
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// jsonType returns the JSON type of a value
+func jsonType(raw json.RawMessage) string {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return "nothing"
+	}
+	switch trimmed[0] {
+	case '"':
+		return "string"
+	case '[':
+		return "array"
+	case '{':
+		return "object"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
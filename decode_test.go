@@ -0,0 +1,445 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// capturedHello is the response of the single translation endpoint for "Hello" from English to German
+const capturedHello = `[[["Hallo","Hello",null,null,10]],null,"en",null,null,[["Hello",null,[["Hallo",1000,true,false],["Guten Tag",1000,true,false]],[[0,5]],"Hello",0,0]],1,[],[["en"],null,[1],["en"]]]`
+
+// capturedBatch is the response of the batch endpoint for "Hello" and "World" with automatic detection
+const capturedBatch = `[["<pre><a i=\"0\">Hallo</a></pre>","en"],["<pre><a i=\"1\">Welt</a></pre>","en"]]`
+
+func TestParseTranslationJSONDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []DecodeDiagnostic
+	}{
+		{
+			name: "captured response",
+			body: capturedHello,
+		},
+		{
+			name: "unknown top-level position",
+			body: `[[["Hallo","Hello",null,null,10]],null,"en",{"new":1}]`,
+			want: []DecodeDiagnostic{
+				{Path: "[3]", Problem: ProblemUnknownPosition, Found: "object"},
+			},
+		},
+		{
+			name: "unknown sentence position",
+			body: `[[["Hallo","Hello",null,null,10,null,null,null,null,"extra"]],null,"en"]`,
+			want: []DecodeDiagnostic{
+				{Path: "[0][0][9]", Problem: ProblemUnknownPosition, Found: "string"},
+			},
+		},
+		{
+			name: "mistyped confidence",
+			body: `[[["Hallo","Hello",null,null,10]],null,"en",null,null,null,"high"]`,
+			want: []DecodeDiagnostic{
+				{Path: "[6]", Problem: ProblemMistypedPosition, Expected: "number", Found: "string"},
+			},
+		},
+		{
+			name: "mistyped sentence frequency",
+			body: `[[["Hallo","Hello",null,null,"10"]],null,"en"]`,
+			want: []DecodeDiagnostic{
+				{Path: "[0][0][4]", Problem: ProblemMistypedPosition, Expected: "number", Found: "string"},
+			},
+		},
+		{
+			name: "mistyped alternative flag",
+			body: `[[["Hallo","Hello",null,null,10]],null,"en",null,null,[["Hello",null,[["Hallo",1000,"yes",false]],[[0,5]],"Hello",0,0]]]`,
+			want: []DecodeDiagnostic{
+				{Path: "[5][0][2][0][2]", Problem: ProblemMistypedPosition, Expected: "boolean", Found: "string"},
+			},
+		},
+		{
+			name: "unknown position in a nested list",
+			body: `[[["Hallo","Hello",null,null,10]],null,"en",null,null,null,null,null,null,null,null,[["interjection",[[["hi"],"id",[["informal"],"extra"]]],"hello",1]]]`,
+			want: []DecodeDiagnostic{
+				{Path: "[11][0][1][0][2][1]", Problem: ProblemUnknownPosition, Found: "string"},
+			},
+		},
+		{
+			name: "row that is not an array",
+			body: `[[["Hallo","Hello",null,null,10]],"noun","en"]`,
+			want: []DecodeDiagnostic{
+				{Path: "[1]", Problem: ProblemMistypedPosition, Expected: "array", Found: "string"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, diagnostics, err := parseTranslationJSON([]byte(tt.body), SectionAll)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Translation != "Hallo" {
+				t.Errorf("got translation %q, want %q", result.Translation, "Hallo")
+			}
+			if len(diagnostics) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(diagnostics, tt.want) {
+					t.Errorf("got diagnostics %v, want %v", diagnostics, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRelatedWords(t *testing.T) {
+	// Row 14 holds the related words, the rows after it are not known
+	body := `[[["Haus","house",null,null,10]],null,"en",null,null,null,null,null,null,null,null,null,null,null,[["home","housing"],["houses"]],[["extra"]]]`
+	result, diagnostics, err := parseTranslationJSON([]byte(body), SectionAll)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []RelatedWordGroup{{Words: []string{"home", "housing"}}, {Words: []string{"houses"}}}
+	if !reflect.DeepEqual(result.RelatedWords, want) {
+		t.Errorf("got related words %v, want %v", result.RelatedWords, want)
+	}
+	wantDiagnostics := []DecodeDiagnostic{{Path: "[15]", Problem: ProblemUnknownPosition, Found: "array"}}
+	if !reflect.DeepEqual(diagnostics, wantDiagnostics) {
+		t.Errorf("got diagnostics %v, want %v", diagnostics, wantDiagnostics)
+	}
+}
+
+func TestParseSpellingCorrection(t *testing.T) {
+	tests := []struct {
+		name              string
+		body              string
+		sections          Section
+		wantText          string
+		wantHTML          string
+		wantAutocorrected bool
+		wantSuggested     string
+		wantDiagnostics   []DecodeDiagnostic
+	}{
+		{
+			name:     "suggested correction",
+			body:     `[[["Hallo Welt","helo wrld",null,null,10]],null,"en",null,null,null,1,["<b><i>hello</i></b> <b><i>world</i></b>","hello world",null,null,null,false],[["en"],null,[1],["en"]]]`,
+			sections: SectionAll,
+			wantText: "hello world",
+			wantHTML: "<b><i>hello</i></b> <b><i>world</i></b>",
+		},
+		{
+			name:              "applied correction",
+			body:              `[[["Hallo Welt","helo wrld",null,null,10]],null,"en",null,null,null,1,["<b><i>hello</i></b> <b><i>world</i></b>","hello world",null,null,null,true]]`,
+			sections:          SectionAll,
+			wantText:          "hello world",
+			wantHTML:          "<b><i>hello</i></b> <b><i>world</i></b>",
+			wantAutocorrected: true,
+		},
+		{
+			name:          "suggested source language",
+			body:          `[[["Hallo Welt","Hallo Welt",null,null,10]],null,"en",null,null,null,1,null,[["de"],null,[0.9],["de"]]]`,
+			sections:      SectionAll,
+			wantSuggested: "de",
+		},
+		{
+			name:     "section not requested",
+			body:     `[[["Hallo Welt","helo wrld",null,null,10]],null,"en",null,null,null,1,["<b><i>hello</i></b> <b><i>world</i></b>","hello world",null,null,null,true],[["de"],null,[0.9],["de"]]]`,
+			sections: SectionTranslation,
+		},
+		{
+			name:     "mistyped autocorrection flag",
+			body:     `[[["Hallo Welt","helo wrld",null,null,10]],null,"en",null,null,null,1,["<b><i>hello</i></b> world","hello world",null,null,null,"yes"]]`,
+			sections: SectionAll,
+			wantText: "hello world",
+			wantHTML: "<b><i>hello</i></b> world",
+			wantDiagnostics: []DecodeDiagnostic{
+				{Path: "[7][5]", Problem: ProblemMistypedPosition, Expected: "boolean", Found: "string"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, diagnostics, err := parseTranslationJSON([]byte(tt.body), tt.sections)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.CorrectedContent != tt.wantText || result.CorrectedContentHTML != tt.wantHTML {
+				t.Errorf("got correction %q, %q, want %q, %q", result.CorrectedContent, result.CorrectedContentHTML, tt.wantText, tt.wantHTML)
+			}
+			if result.Autocorrected != tt.wantAutocorrected {
+				t.Errorf("got autocorrected %t, want %t", result.Autocorrected, tt.wantAutocorrected)
+			}
+			if result.SuggestedSourceLanguageCode != tt.wantSuggested {
+				t.Errorf("got suggested source language %q, want %q", result.SuggestedSourceLanguageCode, tt.wantSuggested)
+			}
+			if len(diagnostics) != 0 || len(tt.wantDiagnostics) != 0 {
+				if !reflect.DeepEqual(diagnostics, tt.wantDiagnostics) {
+					t.Errorf("got diagnostics %v, want %v", diagnostics, tt.wantDiagnostics)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTransliteration(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		sections      Section
+		wantSentences int
+		wantTarget    string
+		wantSource    string
+	}{
+		{
+			name:          "trailing transliteration row",
+			body:          `[[["Привет","Hello",null,null,10],[null,null,"Privet","Hello"]],null,"en"]`,
+			sections:      SectionAll,
+			wantSentences: 1,
+			wantTarget:    "Privet",
+			wantSource:    "Hello",
+		},
+		{
+			name:          "sentences joined without a trailing row",
+			body:          `[[["Привет. ","Hello. ","Privet. ",null,10],["Мир.","World.","Mir.",null,10]],null,"en"]`,
+			sections:      SectionAll,
+			wantSentences: 2,
+			wantTarget:    "Privet. Mir.",
+		},
+		{
+			name:          "row with a translation is a sentence",
+			body:          `[[["Привет","Hello",null,null,10],["Мир",null,"Mir",null]],null,"en"]`,
+			sections:      SectionAll,
+			wantSentences: 2,
+			wantTarget:    "Mir",
+		},
+		{
+			name:          "row with content is a sentence",
+			body:          `[[["Привет","Hello",null,null,10],[null,"World","Mir",null]],null,"en"]`,
+			sections:      SectionAll,
+			wantSentences: 2,
+			wantTarget:    "Mir",
+		},
+		{
+			name:          "short null row is a sentence",
+			body:          `[[["Привет","Hello",null,null,10],[null,null]],null,"en"]`,
+			sections:      SectionAll,
+			wantSentences: 2,
+		},
+		{
+			name:          "section not requested",
+			body:          `[[["Привет","Hello",null,null,10],[null,null,"Privet","Hello"]],null,"en"]`,
+			sections:      SectionTranslation,
+			wantSentences: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := parseTranslationJSON([]byte(tt.body), tt.sections)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.TranslatedSentences) != tt.wantSentences {
+				t.Errorf("got %d sentences, want %d", len(result.TranslatedSentences), tt.wantSentences)
+			}
+			if result.TargetTransliteration != tt.wantTarget || result.SourceTransliteration != tt.wantSource {
+				t.Errorf("got transliterations %q, %q, want %q, %q", result.TargetTransliteration, result.SourceTransliteration, tt.wantTarget, tt.wantSource)
+			}
+			if result.Pronunciation != result.TargetTransliteration {
+				t.Errorf("got pronunciation %q, want the target transliteration %q", result.Pronunciation, result.TargetTransliteration)
+			}
+		})
+	}
+}
+
+func TestPositionsFinishNested(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		read func(p *positions)
+		want []DecodeDiagnostic
+	}{
+		{
+			name: "partly read list",
+			data: `[["a","b",null]]`,
+			read: func(p *positions) {
+				l := p.list(0)
+				l.string(0)
+				l.finish()
+			},
+			want: []DecodeDiagnostic{{Path: "[0][1]", Problem: ProblemUnknownPosition, Found: "string"}},
+		},
+		{
+			name: "list nested in a list",
+			data: `[[["a",1]]]`,
+			read: func(p *positions) {
+				p.each(0, func(l *positions, j int) {
+					m := l.list(j)
+					m.string(0)
+					m.finish()
+				})
+			},
+			want: []DecodeDiagnostic{{Path: "[0][0][1]", Problem: ProblemUnknownPosition, Found: "number"}},
+		},
+		{
+			name: "fully read lists",
+			data: `[["a","b"],[1,2]]`,
+			read: func(p *positions) {
+				p.strings(0)
+				p.numbers(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newPositions([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.read(p)
+			if diagnostics := p.finish(); len(diagnostics) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(diagnostics, tt.want) {
+					t.Errorf("got diagnostics %v, want %v", diagnostics, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBatchJSONDiagnostics(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		items []batchItem
+		want  []DecodeDiagnostic
+	}{
+		{
+			name: "captured response",
+			body: capturedBatch,
+			items: []batchItem{
+				{Translation: `<pre><a i="0">Hallo</a></pre>`, SourceLanguage: "en"},
+				{Translation: `<pre><a i="1">Welt</a></pre>`, SourceLanguage: "en"},
+			},
+		},
+		{
+			name:  "single pair",
+			body:  `["<pre><a i=\"0\">Hallo</a></pre>","en"]`,
+			items: []batchItem{{Translation: `<pre><a i="0">Hallo</a></pre>`, SourceLanguage: "en"}},
+		},
+		{
+			name:  "unknown item position",
+			body:  `[["<a i=0>Hallo</a>","en","extra"]]`,
+			items: []batchItem{{Translation: "<a i=0>Hallo</a>", SourceLanguage: "en"}},
+			want: []DecodeDiagnostic{
+				{Path: "[0][2]", Problem: ProblemUnknownPosition, Found: "string"},
+			},
+		},
+		{
+			name:  "mistyped item",
+			body:  `[["<a i=0>Hallo</a>","en"],[1,2]]`,
+			items: []batchItem{{Translation: "<a i=0>Hallo</a>", SourceLanguage: "en"}, {}},
+			want: []DecodeDiagnostic{
+				{Path: "[1][0]", Problem: ProblemMistypedPosition, Expected: "string", Found: "number"},
+				{Path: "[1][1]", Problem: ProblemMistypedPosition, Expected: "string", Found: "number"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, diagnostics, err := parseBatchJSON([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := range items {
+				items[i].decoded = decoded{}
+			}
+			if !reflect.DeepEqual(items, tt.items) {
+				t.Errorf("got items %+v, want %+v", items, tt.items)
+			}
+			if len(diagnostics) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(diagnostics, tt.want) {
+					t.Errorf("got diagnostics %v, want %v", diagnostics, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	const drifted = `[[["Hallo","Hello",null,null,10]],null,"en",null,null,null,"high"]`
+	tests := []struct {
+		name        string
+		body        string
+		strict      bool
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "captured lenient", body: capturedHello},
+		{name: "captured strict", body: capturedHello, strict: true},
+		{name: "drift lenient", body: drifted, wantWarning: true},
+		{name: "drift strict", body: drifted, strict: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			var warnings []error
+			client := NewClient(
+				WithTranslateURL(srv.URL),
+				WithTKKProvider(StaticTKK(googleTranslateTKK)),
+				WithLanguageValidation(false),
+				WithStrictDecoding(tt.strict),
+				WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+			)
+			result, err := client.Translate(context.Background(), "Hello", language.English, language.German)
+
+			var schemaErr *SchemaError
+			if tt.wantErr {
+				if !errors.As(err, &schemaErr) || !errors.Is(err, ErrMalformedResponse) {
+					t.Fatalf("got error %v, want a *SchemaError", err)
+				}
+				if len(schemaErr.Diagnostics) != 1 || schemaErr.Diagnostics[0].Path != "[6]" {
+					t.Errorf("got diagnostics %v", schemaErr.Diagnostics)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Translation != "Hallo" {
+				t.Errorf("got translation %q, want %q", result.Translation, "Hallo")
+			}
+			if gotWarning := len(warnings) == 1 && errors.As(warnings[0], &schemaErr); gotWarning != tt.wantWarning {
+				t.Errorf("got warnings %v, want a schema warning: %v", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestStrictDecodingBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[["<a i=0>Hallo</a>","en"],[1,2]]`)
+	}))
+	defer srv.Close()
+
+	lenient := NewClient(WithBatchURL(srv.URL), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false))
+	results, err := lenient.TranslateBatch(context.Background(), []string{"Hello"}, language.English, language.German)
+	if err != nil {
+		t.Fatalf("lenient: unexpected error: %v", err)
+	}
+	if results[0].Translation != "Hallo" {
+		t.Errorf("lenient: got translation %q, want %q", results[0].Translation, "Hallo")
+	}
+
+	strict := lenient.With(WithStrictDecoding(true))
+	var schemaErr *SchemaError
+	if _, err := strict.TranslateBatch(context.Background(), []string{"Hello"}, language.English, language.German); !errors.As(err, &schemaErr) {
+		t.Errorf("strict: got error %v, want a *SchemaError", err)
+	}
+}
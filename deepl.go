@@ -0,0 +1,177 @@
+package gtranslate
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/text/language"
+	"net/http"
+	"strings"
+)
+
+// Define the DeepL API endpoints
+const (
+	deeplAPI     = "https://api.deepl.com/v2"
+	deeplFreeAPI = "https://api-free.deepl.com/v2"
+)
+
+// DeepLTranslator translates with the DeepL API
+type DeepLTranslator struct {
+	client  *Client
+	authKey string
+	baseURL string
+}
+
+// Make sure the DeepL backend implements the interface
+var _ Translator = (*DeepLTranslator)(nil)
+
+// NewDeepLTranslator creates a DeepL backend authenticated with the given key.
+// Keys of the free plan, ending with ":fx", use the free API endpoint.
+func NewDeepLTranslator(authKey string, opts ...Option) *DeepLTranslator {
+	defaultURL := deeplAPI
+	if strings.HasSuffix(authKey, ":fx") {
+		defaultURL = deeplFreeAPI
+	}
+	client, baseURL := newBackendClient(defaultURL, opts)
+	return &DeepLTranslator{
+		client:  client,
+		authKey: authKey,
+		baseURL: baseURL,
+	}
+}
+
+// Name returns the identifier of the DeepL backend
+func (t *DeepLTranslator) Name() string {
+	return "deepl"
+}
+
+// Translate translates a single piece of content as a batch of one item
+func (t *DeepLTranslator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	return translateAsBatch(ctx, t, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch translates several pieces of content in a single request
+func (t *DeepLTranslator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	sourceLanguage, targetLanguage, err := t.client.resolveLanguages(sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	return t.translateBatch(ctx, contents, sourceLanguage, targetLanguage)
+}
+
+// translateBatch translates several pieces of content between the given languages, detecting the
+// source language when it is the root tag
+func (t *DeepLTranslator) translateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	target, err := t.client.codecCode(DeepLTargetLanguageCodec, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	request := map[string]interface{}{
+		"text":        contents,
+		"target_lang": target,
+	}
+	if !sourceLanguage.IsRoot() {
+		if request["source_lang"], err = t.client.codecCode(DeepLSourceLanguageCodec, sourceLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	var response struct {
+		Translations []struct {
+			DetectedSourceLanguage string `json:"detected_source_language"`
+			Text                   string `json:"text"`
+		} `json:"translations"`
+	}
+	err = t.client.doJSON(ctx, "POST", t.baseURL+"/translate", t.header(), request, &response, countCharacters(contents))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Translations) != len(contents) {
+		return nil, fmt.Errorf("%w: expected %d translations, got %d", ErrMalformedResponse, len(contents), len(response.Translations))
+	}
+
+	results := make([]BatchResult, len(contents))
+	for i, translation := range response.Translations {
+		results[i] = BatchResult{
+			Index:          i,
+			Content:        contents[i],
+			Translation:    translation.Text,
+			SourceLanguage: sourceLanguage,
+		}
+		if translation.DetectedSourceLanguage != "" {
+			if results[i].SourceLanguage, err = t.client.codecTag(DeepLSourceLanguageCodec, translation.DetectedSourceLanguage); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// Detect detects the language of the content. DeepL has no detection endpoint,
+// so the content is translated and the detected source language is returned without a confidence.
+// The source language is always detected, regardless of the client's default source language.
+func (t *DeepLTranslator) Detect(ctx context.Context, content string) (*Detection, error) {
+	results, err := t.translateBatch(ctx, []string{content}, language.Und, language.AmericanEnglish)
+	if err != nil {
+		return nil, err
+	}
+	return &Detection{Language: results[0].SourceLanguage}, nil
+}
+
+// SupportedLanguages lists the source and target languages supported by DeepL.
+// DeepL only provides English names, so the display language is ignored.
+func (t *DeepLTranslator) SupportedLanguages(ctx context.Context, _ language.Tag) (*Languages, error) {
+	source, err := t.languages(ctx, "source", DeepLSourceLanguageCodec)
+	if err != nil {
+		return nil, err
+	}
+	target, err := t.languages(ctx, "target", DeepLTargetLanguageCodec)
+	if err != nil {
+		return nil, err
+	}
+	return &Languages{Source: source, Target: target}, nil
+}
+
+// languages lists the languages of the given type, source or target, mapped with the codec of that type
+func (t *DeepLTranslator) languages(ctx context.Context, languageType string, codec *LanguageCodec) ([]Language, error) {
+	var response []struct {
+		Language string `json:"language"`
+		Name     string `json:"name"`
+	}
+	err := t.client.doJSON(ctx, "GET", t.baseURL+"/languages?type="+languageType, t.header(), nil, &response, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]Language, len(response))
+	for i, l := range response {
+		languages[i] = Language{Tag: listedTag(codec, l.Language), Code: l.Language, Name: l.Name}
+	}
+	return languages, nil
+}
+
+// header returns the authentication header of DeepL requests
+func (t *DeepLTranslator) header() http.Header {
+	return http.Header{"Authorization": {"DeepL-Auth-Key " + t.authKey}}
+}
+
+// DeepLSourceLanguageCodec maps tags to the source language codes of DeepL, which are base languages only
+var DeepLSourceLanguageCodec = NewLanguageCodec("deepl", []string{
+	"AR", "BG", "CS", "DA", "DE", "EL", "EN", "ES", "ET", "FI", "FR", "HU", "ID", "IT", "JA", "KO",
+	"LT", "LV", "NB", "NL", "PL", "PT", "RO", "RU", "SK", "SL", "SV", "TR", "UK", "ZH",
+}, map[string]string{
+	// ZH covers both scripts, which the matcher would not resolve for traditional Chinese
+	"zh":      "ZH",
+	"zh-Hant": "ZH",
+	"no":      "NB",
+})
+
+// DeepLTargetLanguageCodec maps tags to the target language codes of DeepL, which require the regional
+// variant of English and Portuguese and the script of Chinese. Tags without a region or script map to
+// the most common variant, so en is sent as EN-US, pt as PT-BR and zh as ZH-HANS.
+var DeepLTargetLanguageCodec = NewLanguageCodec("deepl", []string{
+	"AR", "BG", "CS", "DA", "DE", "EL", "EN-GB", "EN-US", "ES", "ET", "FI", "FR", "HU", "ID", "IT", "JA",
+	"KO", "LT", "LV", "NB", "NL", "PL", "PT-BR", "PT-PT", "RO", "RU", "SK", "SL", "SV", "TR", "UK",
+	"ZH-HANS", "ZH-HANT",
+}, map[string]string{
+	"no": "NB",
+})
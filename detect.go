@@ -0,0 +1,51 @@
+package gtranslate
+
+import (
+	"context"
+)
+
+// Detect Function to detect the language of the content using the default client
+func Detect(ctx context.Context, content string) (*Detection, error) {
+	return defaultClient.Detect(ctx, content)
+}
+
+// DetectBatch Function to detect the language of several pieces of content using the default client
+func DetectBatch(ctx context.Context, contents []string) ([]Detection, error) {
+	return defaultClient.DetectBatch(ctx, contents)
+}
+
+// Name returns the identifier of the Google backend using the gtx client
+func (c *Client) Name() string {
+	return "google-gtx"
+}
+
+// Detect detects the language of the content, with the confidence reported by Google.
+// Only the first chunk of long content is sent, since it is enough to detect the language,
+// and only the sections needed for the detection are requested.
+func (c *Client) Detect(ctx context.Context, content string) (*Detection, error) {
+	if c.maxChunkLength > 0 {
+		if chunks := splitText(content, c.maxChunkLength); len(chunks) > 1 {
+			content = chunks[0].text
+		}
+	}
+
+	result, err := c.With(WithSections(SectionTranslation|SectionLanguageDetection)).translateSingle(ctx, content, "auto", "en")
+	if err != nil {
+		return nil, err
+	}
+	return &Detection{Language: result.SourceLanguage, Confidence: result.SourceLanguageConfidence}, nil
+}
+
+// DetectBatch detects the language of several pieces of content and returns one detection per input, in input order.
+// The batch endpoint does not report a confidence, so each piece of content is detected with its own request.
+func (c *Client) DetectBatch(ctx context.Context, contents []string) ([]Detection, error) {
+	detections := make([]Detection, len(contents))
+	for i, content := range contents {
+		detection, err := c.Detect(ctx, content)
+		if err != nil {
+			return nil, err
+		}
+		detections[i] = *detection
+	}
+	return detections, nil
+}
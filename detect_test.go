@@ -0,0 +1,35 @@
+package gtranslate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestDetectFirstChunk(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		queries = append(queries, r.Form.Get("q"))
+		fmt.Fprint(w, `[[["Hello","Bonjour",null,null,10]],null,"fr",null,null,null,null,null,[["fr"],null,[0.98],["fr"]]]`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithTranslateURL(srv.URL), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false), WithMaxChunkLength(20))
+	detections, err := client.DetectBatch(context.Background(), []string{"Bonjour.", "Bonjour tout le monde. Comment allez-vous ?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, detection := range detections {
+		if detection.Language != language.French || detection.Confidence != 0.98 {
+			t.Errorf("item %d: got %s with confidence %v, want fr with 0.98", i, detection.Language, detection.Confidence)
+		}
+	}
+	if want := []string{"Bonjour.", "Bonjour tout le"}; fmt.Sprint(queries) != fmt.Sprint(want) {
+		t.Errorf("got queries %q, want one request per input with the first chunk only", queries)
+	}
+}
@@ -0,0 +1,162 @@
+package gtranslate
+
+import (
+	"strings"
+)
+
+// PartOfSpeech is the part of speech of a dictionary entry, as numbered by Google Translate.
+// Unlike WordTranslation.PartsOfSentence, it does not depend on the display language.
+type PartOfSpeech int
+
+// Define the parts of speech of dictionary entries
+const (
+	PartOfSpeechUnknown PartOfSpeech = iota
+	PartOfSpeechNoun
+	PartOfSpeechVerb
+	PartOfSpeechAdjective
+	PartOfSpeechAdverb
+	PartOfSpeechPreposition
+	PartOfSpeechAbbreviation
+	PartOfSpeechConjunction
+	PartOfSpeechPronoun
+	PartOfSpeechInterjection
+	PartOfSpeechPhrase
+	PartOfSpeechPrefix
+	PartOfSpeechSuffix
+	PartOfSpeechArticle
+	PartOfSpeechCombiningForm
+	PartOfSpeechNumeral
+	PartOfSpeechAuxiliaryVerb
+	PartOfSpeechExclamation
+	PartOfSpeechPlural
+	PartOfSpeechParticle
+)
+
+// partOfSpeechNames lists the English name of each part of speech
+var partOfSpeechNames = []string{
+	"unknown", "noun", "verb", "adjective", "adverb", "preposition", "abbreviation", "conjunction", "pronoun",
+	"interjection", "phrase", "prefix", "suffix", "article", "combining form", "numeral", "auxiliary verb",
+	"exclamation", "plural", "particle",
+}
+
+// String returns the English name of the part of speech
+func (p PartOfSpeech) String() string {
+	if p < 0 || int(p) >= len(partOfSpeechNames) {
+		return "unknown"
+	}
+	return partOfSpeechNames[p]
+}
+
+// Gender is the grammatical gender of a dictionary entry
+type Gender int
+
+// Define the grammatical genders
+const (
+	GenderUnknown   Gender = iota // The gender is not known, or the language has no grammatical gender.
+	GenderMasculine               // The word is masculine.
+	GenderFeminine                // The word is feminine.
+	GenderNeuter                  // The word is neuter.
+)
+
+// String returns the name of the gender
+func (g Gender) String() string {
+	switch g {
+	case GenderMasculine:
+		return "masculine"
+	case GenderFeminine:
+		return "feminine"
+	case GenderNeuter:
+		return "neuter"
+	default:
+		return "unknown"
+	}
+}
+
+// articleGenders maps the definite articles returned with dictionary entries to their gender.
+// Dictionary entries are singular, so "die" is taken as feminine; plural-only articles such as "les" are not listed.
+var articleGenders = map[string]Gender{
+	"der": GenderMasculine, // German
+	"die": GenderFeminine,  // German
+	"das": GenderNeuter,    // German
+	"le":  GenderMasculine, // French
+	"el":  GenderMasculine, // Spanish
+	"il":  GenderMasculine, // Italian
+	"lo":  GenderMasculine, // Italian
+	"la":  GenderFeminine,  // French, Spanish and Italian
+	"o":   GenderMasculine, // Portuguese
+	"a":   GenderFeminine,  // Portuguese
+	"het": GenderNeuter,    // Dutch
+}
+
+// genderOfArticle returns the gender given by a definite article
+func genderOfArticle(article string) Gender {
+	return articleGenders[strings.ToLower(strings.TrimSpace(article))]
+}
+
+// FrequencyTier groups the frequency scores of dictionary entries the way Google Translate displays them
+type FrequencyTier int
+
+// Define the frequency tiers
+const (
+	FrequencyUnknown  FrequencyTier = iota // The response has no frequency score for the translation.
+	FrequencyRare                          // The translation is rarely used.
+	FrequencyUncommon                      // The translation is used occasionally.
+	FrequencyCommon                        // The translation is commonly used.
+)
+
+// String returns the name of the frequency tier
+func (f FrequencyTier) String() string {
+	switch f {
+	case FrequencyCommon:
+		return "common"
+	case FrequencyUncommon:
+		return "uncommon"
+	case FrequencyRare:
+		return "rare"
+	default:
+		return "unknown"
+	}
+}
+
+// FrequencyThresholds holds the lowest frequency scores of the uncommon and common tiers
+type FrequencyThresholds struct {
+	Uncommon float64 // The lowest score of an uncommon translation.
+	Common   float64 // The lowest score of a common translation.
+}
+
+// DefaultFrequencyThresholds are the thresholds used unless WithFrequencyThresholds is given.
+// Google does not publish how its scores map to the bars of the web page, so these are heuristics:
+// the most frequent senses of everyday words score above 0.05, and senses scoring below 0.0025
+// are typically shown with a single bar.
+var DefaultFrequencyThresholds = FrequencyThresholds{Uncommon: 0.0025, Common: 0.05}
+
+// WithFrequencyThresholds sets the scores from which dictionary entries are classified as uncommon or common
+func WithFrequencyThresholds(thresholds FrequencyThresholds) Option {
+	return func(c *Client) {
+		c.frequencyThresholds = thresholds
+	}
+}
+
+// Tier returns the tier of a frequency score. A score that is not positive is unknown.
+func (th FrequencyThresholds) Tier(score float64) FrequencyTier {
+	switch {
+	case score <= 0:
+		return FrequencyUnknown
+	case score >= th.Common:
+		return FrequencyCommon
+	case score >= th.Uncommon:
+		return FrequencyUncommon
+	default:
+		return FrequencyRare
+	}
+}
+
+// classify sets the frequency tier of every sense of the dictionary entries of the result
+func (th FrequencyThresholds) classify(result *TranslationResult) {
+	for i := range result.WordTranslations {
+		equivalents := result.WordTranslations[i].Equivalents
+		for j := range equivalents {
+			equivalents[j].FrequencyTier = th.Tier(equivalents[j].Frequency)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package gtranslate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestGenderOfArticle(t *testing.T) {
+	tests := []struct {
+		article string
+		want    Gender
+	}{
+		{"der", GenderMasculine},
+		{"die", GenderFeminine},
+		{"das", GenderNeuter},
+		{" Le ", GenderMasculine},
+		{"la", GenderFeminine},
+		{"El", GenderMasculine},
+		{"het", GenderNeuter},
+		{"les", GenderUnknown},
+		{"", GenderUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.article, func(t *testing.T) {
+			if got := genderOfArticle(tt.article); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrequencyThresholdsTier(t *testing.T) {
+	custom := FrequencyThresholds{Uncommon: 0.1, Common: 0.5}
+	tests := []struct {
+		name       string
+		thresholds FrequencyThresholds
+		score      float64
+		want       FrequencyTier
+	}{
+		{"no score", DefaultFrequencyThresholds, 0, FrequencyUnknown},
+		{"negative score", DefaultFrequencyThresholds, -1, FrequencyUnknown},
+		{"rare", DefaultFrequencyThresholds, 0.001, FrequencyRare},
+		{"uncommon threshold", DefaultFrequencyThresholds, 0.0025, FrequencyUncommon},
+		{"uncommon", DefaultFrequencyThresholds, 0.01, FrequencyUncommon},
+		{"common threshold", DefaultFrequencyThresholds, 0.05, FrequencyCommon},
+		{"common", DefaultFrequencyThresholds, 0.8, FrequencyCommon},
+		{"custom rare", custom, 0.05, FrequencyRare},
+		{"custom uncommon", custom, 0.1, FrequencyUncommon},
+		{"custom common", custom, 0.5, FrequencyCommon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.thresholds.Tier(tt.score); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrequencyTierString(t *testing.T) {
+	tests := []struct {
+		tier FrequencyTier
+		want string
+	}{
+		{FrequencyUnknown, "unknown"},
+		{FrequencyRare, "rare"},
+		{FrequencyUncommon, "uncommon"},
+		{FrequencyCommon, "common"},
+		{FrequencyTier(42), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.tier.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDictionaryEntries(t *testing.T) {
+	// Row 1 holds the dictionary: [part of speech, [translations], [[translation, [reverse], null, score, article]], base form, part of speech number]
+	response := []interface{}{
+		[]interface{}{[]interface{}{"Haus", "house", nil, nil, 10}},
+		[]interface{}{[]interface{}{"noun", []interface{}{"Haus", "Gebäude", "Heim"}, []interface{}{
+			[]interface{}{"Haus", []interface{}{"house", "home"}, nil, 0.6, "das"},
+			[]interface{}{"Gebäude", []interface{}{"building", "house"}, nil, 0.01, "das"},
+			[]interface{}{"Heim", []interface{}{"home"}, nil, 0.001, "das"},
+			[]interface{}{"Sippe", []interface{}{"house"}, nil, nil, "die"},
+		}, "house", 1}},
+		"en",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    []FrequencyTier
+	}{
+		{"default thresholds", nil, []FrequencyTier{FrequencyCommon, FrequencyUncommon, FrequencyRare, FrequencyUnknown}},
+		{
+			name:    "custom thresholds",
+			options: []Option{WithFrequencyThresholds(FrequencyThresholds{Uncommon: 0.001, Common: 0.01})},
+			want:    []FrequencyTier{FrequencyCommon, FrequencyCommon, FrequencyUncommon, FrequencyUnknown},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{
+				WithTranslateURL(srv.URL),
+				WithTKKProvider(StaticTKK(googleTranslateTKK)),
+				WithLanguageValidation(false),
+			}, tt.options...)
+			result, err := NewClient(options...).Translate(context.Background(), "house", language.English, language.German)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.WordTranslations) != 1 {
+				t.Fatalf("got %d dictionary entries, want 1", len(result.WordTranslations))
+			}
+			equivalents := result.WordTranslations[0].Equivalents
+			if len(equivalents) != len(tt.want) {
+				t.Fatalf("got %d senses, want %d", len(equivalents), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if equivalents[i].FrequencyTier != want {
+					t.Errorf("sense %q: got tier %s, want %s", equivalents[i].Content, equivalents[i].FrequencyTier, want)
+				}
+			}
+			if equivalents[0].Gender != GenderNeuter || equivalents[3].Gender != GenderFeminine {
+				t.Errorf("got genders %v and %v, want neuter and feminine", equivalents[0].Gender, equivalents[3].Gender)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package gtranslate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxErrorBodyLength is the maximum number of response body bytes kept in an APIError
+const maxErrorBodyLength = 512
+
+// Sentinel errors classifying API failures. Use errors.Is to test for them.
+var (
+	ErrRateLimited       = errors.New("rate limited by translation api")
+	ErrBlocked           = errors.New("blocked by translation api")
+	ErrServer            = errors.New("server error in translation api")
+	ErrRequestTooLong    = errors.New("request too long for translation api")
+	ErrUnexpectedStatus  = errors.New("unexpected status from translation api")
+	ErrMalformedResponse = errors.New("unable to parse the response from translation api")
+	ErrCircuitOpen       = errors.New("circuit breaker is open")
+)
+
+// Sentinel errors reporting invalid request languages
+var (
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrNoTargetLanguage    = errors.New("no target language given and no default target language configured")
+)
+
+// APIError is returned when the API responds with a non-200 status.
+// It unwraps to one of the sentinel errors above.
+type APIError struct {
+	StatusCode int           // The HTTP status code of the response.
+	RetryAfter time.Duration // The delay requested by the Retry-After header, zero if absent.
+	Header     http.Header   // The headers of the response.
+	Body       string        // The beginning of the response body, truncated to maxErrorBodyLength bytes.
+	Err        error         // The sentinel error classifying the failure.
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%v: status %d", e.Err, e.StatusCode)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %s", e.RetryAfter)
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error classifying the failure
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError builds an APIError from a non-200 response and the beginning of its body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Header:     resp.Header,
+		Body:       string(body),
+		Err:        classifyStatus(resp.StatusCode),
+	}
+}
+
+// classifyStatus maps an HTTP status code to a sentinel error
+func classifyStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusForbidden, statusCode >= 300 && statusCode < 400:
+		// Google redirects blocked clients to a captcha page
+		return ErrBlocked
+	case statusCode == http.StatusRequestEntityTooLarge, statusCode == http.StatusRequestURITooLong:
+		return ErrRequestTooLong
+	case statusCode >= 500:
+		return ErrServer
+	default:
+		return ErrUnexpectedStatus
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// BatchError reports the items of a batch request that could not be recovered from the response
+type BatchError struct {
+	Indexes []int // The indexes of the failed items, in input order.
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch items %v could not be recovered from the response", e.Indexes)
+}
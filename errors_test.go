@@ -0,0 +1,118 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       error
+	}{
+		{http.StatusMovedPermanently, ErrBlocked},
+		{http.StatusFound, ErrBlocked},
+		{http.StatusTemporaryRedirect, ErrBlocked},
+		{http.StatusForbidden, ErrBlocked},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusRequestEntityTooLarge, ErrRequestTooLong},
+		{http.StatusRequestURITooLong, ErrRequestTooLong},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusBadGateway, ErrServer},
+		{http.StatusServiceUnavailable, ErrServer},
+		{http.StatusBadRequest, ErrUnexpectedStatus},
+		{http.StatusNotFound, ErrUnexpectedStatus},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			if got := classifyStatus(tt.statusCode); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{"absent", "", 0, 0},
+		{"seconds", "120", 120 * time.Second, 120 * time.Second},
+		{"zero seconds", "0", 0, 0},
+		{"negative seconds", "-5", 0, 0},
+		{"HTTP date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+		{"HTTP date in the past", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+		{"malformed", "soon", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("got %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	body := strings.Repeat("x", 2*maxErrorBodyLength)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithTranslateURL(srv.URL), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false))
+	_, err := client.Translate(context.Background(), "Hello", language.English, language.German)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got error %v, want ErrRateLimited", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != 30*time.Second {
+		t.Errorf("got status %d and retry after %s, want 429 and 30s", apiErr.StatusCode, apiErr.RetryAfter)
+	}
+	if apiErr.Body != body[:maxErrorBodyLength] {
+		t.Errorf("got a body of %d bytes, want the first %d", len(apiErr.Body), maxErrorBodyLength)
+	}
+	if apiErr.Header.Get("Retry-After") != "30" {
+		t.Errorf("got headers %v, want the response headers", apiErr.Header)
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[[["Hallo","Hello",null,null,10]],null,"en",{"new":1}]`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(
+		WithTranslateURL(srv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithLanguageValidation(false),
+		WithStrictDecoding(true),
+	)
+	_, err := client.Translate(context.Background(), "Hello", language.English, language.German)
+	if !errors.Is(err, ErrMalformedResponse) {
+		t.Fatalf("got error %v, want ErrMalformedResponse", err)
+	}
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("got error %v, want a *SchemaError", err)
+	}
+	if len(schemaErr.Diagnostics) != 1 || schemaErr.Diagnostics[0].Path != "[3]" {
+		t.Errorf("got diagnostics %v, want the unknown position [3]", schemaErr.Diagnostics)
+	}
+}
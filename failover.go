@@ -0,0 +1,197 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/text/language"
+	"net"
+	"time"
+)
+
+// Define defaults of the circuit breakers of a failover chain
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = time.Second * 30
+)
+
+// FailoverTranslator tries an ordered list of backends, moving on to the next one when a backend
+// fails with a rate limiting, blocking, server, malformed response or network error.
+// Each backend has a circuit breaker, so that a backend that keeps failing is skipped until its cool-down has elapsed.
+type FailoverTranslator struct {
+	translators   []Translator
+	breakers      []*circuitBreaker
+	onStateChange CircuitStateChange
+}
+
+// Make sure the failover chain implements the interface
+var _ Translator = (*FailoverTranslator)(nil)
+
+// FailoverOption configures a FailoverTranslator
+type FailoverOption func(*failoverConfig)
+
+// failoverConfig holds the settings of a FailoverTranslator
+type failoverConfig struct {
+	threshold     int
+	cooldown      time.Duration
+	onStateChange CircuitStateChange
+}
+
+// WithFailoverBreaker sets the number of consecutive failures after which a backend is skipped,
+// and how long it is skipped before a trial call is let through
+func WithFailoverBreaker(threshold int, cooldown time.Duration) FailoverOption {
+	return func(cfg *failoverConfig) {
+		cfg.threshold = threshold
+		cfg.cooldown = cooldown
+	}
+}
+
+// WithFailoverStateChange sets a callback observing the state changes of the circuit breakers,
+// called with the name of the backend
+func WithFailoverStateChange(onStateChange CircuitStateChange) FailoverOption {
+	return func(cfg *failoverConfig) {
+		cfg.onStateChange = onStateChange
+	}
+}
+
+// NewFailoverTranslator creates a chain trying the translators in the given order
+func NewFailoverTranslator(translators []Translator, opts ...FailoverOption) *FailoverTranslator {
+	cfg := failoverConfig{threshold: defaultBreakerThreshold, cooldown: defaultBreakerCooldown}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	f := &FailoverTranslator{
+		translators:   translators,
+		breakers:      make([]*circuitBreaker, len(translators)),
+		onStateChange: cfg.onStateChange,
+	}
+	for i, translator := range translators {
+		f.breakers[i] = newCircuitBreaker(translator.Name(), cfg.threshold, cfg.cooldown)
+	}
+	return f
+}
+
+// Name returns the identifier of the failover chain
+func (f *FailoverTranslator) Name() string {
+	return "failover"
+}
+
+// State returns the state of the circuit breaker of the backend with the given name
+func (f *FailoverTranslator) State(name string) CircuitState {
+	for i, translator := range f.translators {
+		if translator.Name() == name {
+			return f.breakers[i].State()
+		}
+	}
+	return CircuitClosed
+}
+
+// Translate translates a single piece of content with the first available backend.
+// The Provider field of the result names the backend that served it.
+func (f *FailoverTranslator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	var result *TranslationResult
+	name, err := f.try(ctx, func(t Translator) error {
+		var err error
+		result, err = t.Translate(ctx, content, sourceLanguage, targetLanguage)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = name
+	return result, nil
+}
+
+// TranslateBatch translates several pieces of content with the first available backend.
+// The Provider field of each result names the backend that served it. When the backend could not
+// translate some items, its results are returned together with its *BatchError.
+func (f *FailoverTranslator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	var results []BatchResult
+	name, err := f.try(ctx, func(t Translator) error {
+		var err error
+		results, err = t.TranslateBatch(ctx, contents, sourceLanguage, targetLanguage)
+		return err
+	})
+	var batchErr *BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Provider = name
+	}
+	return results, err
+}
+
+// Detect detects the language of the content with the first available backend
+func (f *FailoverTranslator) Detect(ctx context.Context, content string) (*Detection, error) {
+	var detection *Detection
+	_, err := f.try(ctx, func(t Translator) error {
+		var err error
+		detection, err = t.Detect(ctx, content)
+		return err
+	})
+	return detection, err
+}
+
+// SupportedLanguages lists the languages of the first available backend
+func (f *FailoverTranslator) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	var languages *Languages
+	_, err := f.try(ctx, func(t Translator) error {
+		var err error
+		languages, err = t.SupportedLanguages(ctx, displayLanguage)
+		return err
+	})
+	return languages, err
+}
+
+// try calls each backend in order until one succeeds or fails with an error that does not warrant
+// a failover, and returns the name of the backend that served the call, or that failed it.
+// When every backend fails, the errors of all backends are joined.
+func (f *FailoverTranslator) try(ctx context.Context, call func(Translator) error) (string, error) {
+	if len(f.translators) == 0 {
+		return "", errors.New("no translators in failover chain")
+	}
+
+	var errs []error
+	for i, translator := range f.translators {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		breaker := f.breakers[i]
+		if err := breaker.allow(f.onStateChange); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", translator.Name(), err))
+			continue
+		}
+
+		err := call(translator)
+		switch {
+		case err == nil:
+			breaker.record(true, f.onStateChange)
+			return translator.Name(), nil
+		case ctx.Err() != nil:
+			// The caller gave up, which says nothing about the health of the backend
+			breaker.release()
+			return "", err
+		case isFailoverError(err):
+			breaker.record(false, f.onStateChange)
+			errs = append(errs, fmt.Errorf("%s: %w", translator.Name(), err))
+		default:
+			breaker.release()
+			return translator.Name(), fmt.Errorf("%s: %w", translator.Name(), err)
+		}
+	}
+	return "", errors.Join(errs...)
+}
+
+// isFailoverError reports whether a backend failing with err should be replaced by the next one
+func isFailoverError(err error) bool {
+	for _, target := range []error{ErrRateLimited, ErrBlocked, ErrServer, ErrMalformedResponse, ErrCircuitOpen} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
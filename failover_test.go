@@ -0,0 +1,176 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// fakeTranslator is a backend answering with fixed results, or failing with a fixed error
+type fakeTranslator struct {
+	name    string
+	err     error
+	results []BatchResult
+	calls   int
+}
+
+func (f *fakeTranslator) Name() string { return f.name }
+
+func (f *fakeTranslator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &TranslationResult{Content: content, Translation: f.name + ": " + content}, nil
+}
+
+func (f *fakeTranslator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	f.calls++
+	return f.results, f.err
+}
+
+func (f *fakeTranslator) Detect(ctx context.Context, content string) (*Detection, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &Detection{Language: language.English}, nil
+}
+
+func (f *fakeTranslator) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	f.calls++
+	return &Languages{}, f.err
+}
+
+// timeoutError is a network error
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestFailoverErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantFailover bool
+	}{
+		{"rate limited", &APIError{StatusCode: 429, Err: ErrRateLimited}, true},
+		{"blocked", &APIError{StatusCode: 403, Err: ErrBlocked}, true},
+		{"server error", &APIError{StatusCode: 503, Err: ErrServer}, true},
+		{"malformed response", fmt.Errorf("%w: unexpected end of JSON input", ErrMalformedResponse), true},
+		{"circuit open", &CircuitOpenError{Endpoint: "https://example.com", RetryAt: time.Now()}, true},
+		{"network error", fmt.Errorf("send request: %w", timeoutError{}), true},
+		{"no target language", ErrNoTargetLanguage, false},
+		{"unsupported language", fmt.Errorf("%w: xx", ErrUnsupportedLanguage), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := &fakeTranslator{name: "first", err: tt.err}
+			second := &fakeTranslator{name: "second"}
+			f := NewFailoverTranslator([]Translator{first, second})
+
+			result, err := f.Translate(context.Background(), "Hello", language.English, language.German)
+			if !tt.wantFailover {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				if second.calls != 0 {
+					t.Errorf("the second backend was called %d times, want 0", second.calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Provider != "second" || result.Translation != "second: Hello" {
+				t.Errorf("got %q from %q, want the result of the second backend", result.Translation, result.Provider)
+			}
+		})
+	}
+}
+
+func TestFailoverAllBackendsFail(t *testing.T) {
+	first := &fakeTranslator{name: "first", err: &APIError{StatusCode: 429, Err: ErrRateLimited}}
+	second := &fakeTranslator{name: "second", err: &APIError{StatusCode: 500, Err: ErrServer}}
+	f := NewFailoverTranslator([]Translator{first, second})
+
+	_, err := f.Detect(context.Background(), "Hello")
+	if !errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrServer) {
+		t.Errorf("got error %v, want the errors of both backends", err)
+	}
+}
+
+func TestFailoverBreaker(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+	first := &fakeTranslator{name: "first", err: &APIError{StatusCode: 500, Err: ErrServer}}
+	second := &fakeTranslator{name: "second"}
+	var recorder stateRecorder
+	f := NewFailoverTranslator(
+		[]Translator{first, second},
+		WithFailoverBreaker(2, cooldown),
+		WithFailoverStateChange(recorder.record),
+	)
+
+	for i := 0; i < 4; i++ {
+		if _, err := f.Translate(context.Background(), "Hello", language.English, language.German); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	if first.calls != 2 {
+		t.Errorf("the failing backend was called %d times, want 2 before its circuit opened", first.calls)
+	}
+	if state := f.State("first"); state != CircuitOpen {
+		t.Errorf("got state %s for the failing backend, want open", state)
+	}
+	if state := f.State("second"); state != CircuitClosed {
+		t.Errorf("got state %s for the healthy backend, want closed", state)
+	}
+
+	// The recovered backend serves again once its trial call succeeds
+	time.Sleep(cooldown + 10*time.Millisecond)
+	first.err = nil
+	result, err := f.Translate(context.Background(), "Hello", language.English, language.German)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Provider != "first" {
+		t.Errorf("got provider %q, want %q", result.Provider, "first")
+	}
+	if want := []string{"closed->open", "open->half-open", "half-open->closed"}; !reflect.DeepEqual(recorder.changes, want) {
+		t.Errorf("got state changes %v, want %v", recorder.changes, want)
+	}
+}
+
+func TestFailoverBatchPartialResults(t *testing.T) {
+	batchErr := &BatchError{Indexes: []int{1}}
+	first := &fakeTranslator{
+		name:    "first",
+		results: []BatchResult{{Index: 0, Content: "Hello", Translation: "Hallo"}, {Index: 1, Content: "World"}},
+		err:     batchErr,
+	}
+	second := &fakeTranslator{name: "second"}
+	f := NewFailoverTranslator([]Translator{first, second})
+
+	results, err := f.TranslateBatch(context.Background(), []string{"Hello", "World"}, language.English, language.German)
+	var gotErr *BatchError
+	if !errors.As(err, &gotErr) || !reflect.DeepEqual(gotErr.Indexes, batchErr.Indexes) {
+		t.Fatalf("got error %v, want the *BatchError of the backend", err)
+	}
+	if len(results) != 2 || results[0].Translation != "Hallo" {
+		t.Fatalf("got results %+v, want the partial results of the backend", results)
+	}
+	for _, result := range results {
+		if result.Provider != "first" {
+			t.Errorf("item %d: got provider %q, want %q", result.Index, result.Provider, "first")
+		}
+	}
+	if second.calls != 0 {
+		t.Errorf("the second backend was called %d times, want 0", second.calls)
+	}
+}
@@ -0,0 +1,231 @@
+package gtranslate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/text/language"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// googleTranslateLanguagesAPI lists the languages supported by the translation endpoints
+const googleTranslateLanguagesAPI = "https://translate.googleapis.com/translate_a/l"
+
+// defaultLanguagesExpiry is how long the list of supported languages is cached
+const defaultLanguagesExpiry = time.Hour * 24
+
+// languageCache caches the supported languages per display language
+type languageCache struct {
+	expiry time.Duration
+
+	mu      sync.Mutex
+	entries map[string]languageCacheEntry
+	fetches map[string]*languageFetch
+}
+
+// languageCacheEntry is a cached list of supported languages
+type languageCacheEntry struct {
+	languages *Languages
+	expires   time.Time
+}
+
+// languageFetch is a download of the supported languages in progress, shared by the callers missing the same entry
+type languageFetch struct {
+	done      chan struct{}
+	languages *Languages
+	err       error
+}
+
+// newLanguageCache creates an empty cache keeping entries for the given expiry
+func newLanguageCache(expiry time.Duration) *languageCache {
+	return &languageCache{
+		expiry:  expiry,
+		entries: make(map[string]languageCacheEntry),
+		fetches: make(map[string]*languageFetch),
+	}
+}
+
+// load returns the cached languages for the display language, calling fetch when they are missing or expired.
+// Concurrent misses of the same entry wait for a single fetch and share its outcome. Errors are not cached.
+func (lc *languageCache) load(ctx context.Context, displayLanguage string, fetch func(context.Context) (*Languages, error)) (*Languages, error) {
+	lc.mu.Lock()
+	if entry, ok := lc.entries[displayLanguage]; ok && time.Now().Before(entry.expires) {
+		lc.mu.Unlock()
+		return entry.languages, nil
+	}
+	if f, ok := lc.fetches[displayLanguage]; ok {
+		lc.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.languages, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &languageFetch{done: make(chan struct{})}
+	lc.fetches[displayLanguage] = f
+	lc.mu.Unlock()
+
+	f.languages, f.err = fetch(ctx)
+
+	lc.mu.Lock()
+	delete(lc.fetches, displayLanguage)
+	if f.err == nil {
+		lc.entries[displayLanguage] = languageCacheEntry{languages: f.languages, expires: time.Now().Add(lc.expiry)}
+	}
+	lc.mu.Unlock()
+	close(f.done)
+	return f.languages, f.err
+}
+
+// WithLanguagesURL sets the URL of the endpoint listing the supported languages
+func WithLanguagesURL(apiURL string) Option {
+	return func(c *Client) {
+		c.languagesURL = apiURL
+	}
+}
+
+// WithLanguagesExpiry sets how long the list of supported languages is cached
+func WithLanguagesExpiry(expiry time.Duration) Option {
+	return func(c *Client) {
+		c.languages = newLanguageCache(expiry)
+	}
+}
+
+// WithLanguageValidation sets whether Translate and TranslateBatch check the source and target languages
+// against the supported languages before sending requests, failing with ErrUnsupportedLanguage.
+// Validation is enabled by default. When the list cannot be fetched, the problem is reported to the
+// warning handler and the request is sent unchecked, so that the listing endpoint is not a point of failure.
+func WithLanguageValidation(enabled bool) Option {
+	return func(c *Client) {
+		c.validateLanguages = enabled
+	}
+}
+
+// SupportedLanguages Function to list the languages supported by Google Translate using the default client
+func SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	return defaultClient.SupportedLanguages(ctx, displayLanguage)
+}
+
+// SupportedLanguages lists the source and target languages accepted by Google Translate,
+// named in the display language. A root display language lists English names.
+// The list is cached per display language, and each call returns its own copy of it.
+func (c *Client) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	hl := "en"
+	if !displayLanguage.IsRoot() {
+		var err error
+		if hl, err = c.languageCode(displayLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	languages, err := c.cachedLanguages(ctx, hl)
+	if err != nil {
+		return nil, err
+	}
+	return &Languages{
+		Source: append([]Language(nil), languages.Source...),
+		Target: append([]Language(nil), languages.Target...),
+	}, nil
+}
+
+// cachedLanguages returns the cached languages named in the display language hl, fetching them when missing.
+// The returned value is shared by every caller and must not be modified.
+func (c *Client) cachedLanguages(ctx context.Context, hl string) (*Languages, error) {
+	return c.languages.load(ctx, hl, func(ctx context.Context) (*Languages, error) {
+		return c.fetchLanguages(ctx, hl)
+	})
+}
+
+// validateLanguageCodes checks that the source and target language codes are supported.
+// The "auto" source language is always accepted.
+func (c *Client) validateLanguageCodes(ctx context.Context, sourceCode, targetCode string) error {
+	if !c.validateLanguages {
+		return nil
+	}
+
+	languages, err := c.cachedLanguages(ctx, "en")
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		c.warn(fmt.Errorf("list supported languages: %w", err))
+		return nil
+	}
+	if sourceCode != "auto" && !containsLanguageCode(languages.Source, sourceCode) {
+		return fmt.Errorf("%w: source language %q", ErrUnsupportedLanguage, sourceCode)
+	}
+	if !containsLanguageCode(languages.Target, targetCode) {
+		return fmt.Errorf("%w: target language %q", ErrUnsupportedLanguage, targetCode)
+	}
+	return nil
+}
+
+// containsLanguageCode reports whether the list contains a language with the given code, ignoring case
+func containsLanguageCode(languages []Language, code string) bool {
+	for _, l := range languages {
+		if strings.EqualFold(l.Code, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchLanguages downloads the supported languages named in the display language hl
+func (c *Client) fetchLanguages(ctx context.Context, hl string) (*Languages, error) {
+	u, err := url.Parse(c.languagesURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+	u.RawQuery = url.Values{"client": {"gtx"}, "hl": {hl}}.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	body, err := c.doRequest(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawLanguages struct {
+		Source map[string]string `json:"sl"`
+		Target map[string]string `json:"tl"`
+	}
+	if err := json.Unmarshal(body, &rawLanguages); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+
+	return &Languages{
+		Source: languagesFromNames(rawLanguages.Source),
+		Target: languagesFromNames(rawLanguages.Target),
+	}, nil
+}
+
+// languagesFromNames converts a map of language codes to display names into a list sorted by code.
+// The pseudo-language "auto" used for detection is left out.
+func languagesFromNames(names map[string]string) []Language {
+	languages := make([]Language, 0, len(names))
+	for code, name := range names {
+		if code == "auto" {
+			continue
+		}
+		tag, _ := GoogleLanguageCodec.Tag(code)
+		languages = append(languages, Language{Tag: tag, Code: code, Name: name})
+	}
+	sortLanguages(languages)
+	return languages
+}
+
+// sortLanguages sorts languages by their code
+func sortLanguages(languages []Language) {
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Code < languages[j].Code
+	})
+}
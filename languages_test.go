@@ -0,0 +1,157 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// capturedLanguages is a trimmed response of the endpoint listing the supported languages
+const capturedLanguages = `{"sl":{"auto":"Detect language","en":"English","iw":"Hebrew","zh-CN":"Chinese (Simplified)"},"tl":{"de":"German","en":"English","iw":"Hebrew","zh-TW":"Chinese (Traditional)"}}`
+
+func TestSupportedLanguagesDisplayCode(t *testing.T) {
+	tests := []struct {
+		display language.Tag
+		wantHL  string
+	}{
+		{language.Und, "en"},
+		{language.French, "fr"},
+		{language.MustParse("zh-Hant"), "zh-TW"},
+		{language.MustParse("zh-Hans-CN"), "zh-CN"},
+		{language.Hebrew, "iw"},
+		{language.MustParse("en-GB"), "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.display.String(), func(t *testing.T) {
+			var hl string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hl = r.URL.Query().Get("hl")
+				fmt.Fprint(w, capturedLanguages)
+			}))
+			defer srv.Close()
+
+			client := NewClient(WithLanguagesURL(srv.URL))
+			languages, err := client.SupportedLanguages(context.Background(), tt.display)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hl != tt.wantHL {
+				t.Errorf("got hl=%q, want %q", hl, tt.wantHL)
+			}
+			if len(languages.Source) != 3 || len(languages.Target) != 4 {
+				t.Errorf("got %d source and %d target languages, want 3 and 4", len(languages.Source), len(languages.Target))
+			}
+		})
+	}
+}
+
+func TestSupportedLanguagesSingleFetch(t *testing.T) {
+	var fetches int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		fmt.Fprint(w, capturedLanguages)
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithLanguagesURL(srv.URL))
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.SupportedLanguages(context.Background(), language.English)
+			errs <- err
+		}()
+	}
+	// The other callers miss the cache while the first fetch is in flight
+	for atomic.LoadInt32(&fetches) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("got %d fetches for concurrent misses, want 1", n)
+	}
+}
+
+func TestLanguageValidation(t *testing.T) {
+	languagesSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, capturedLanguages)
+	}))
+	defer languagesSrv.Close()
+	translateSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, capturedHello)
+	}))
+	defer translateSrv.Close()
+
+	client := NewClient(
+		WithTranslateURL(translateSrv.URL),
+		WithLanguagesURL(languagesSrv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+	)
+	if _, err := client.Translate(context.Background(), "Hello", language.English, language.German); err != nil {
+		t.Errorf("supported languages: unexpected error: %v", err)
+	}
+	if _, err := client.Translate(context.Background(), "Hello", language.Und, language.MustParse("zh-Hant")); err != nil {
+		t.Errorf("mapped target language: unexpected error: %v", err)
+	}
+	if _, err := client.Translate(context.Background(), "Hello", language.English, language.French); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("unsupported target: got error %v, want ErrUnsupportedLanguage", err)
+	}
+	if _, err := client.Translate(context.Background(), "Hello", language.German, language.English); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("unsupported source: got error %v, want ErrUnsupportedLanguage", err)
+	}
+
+	unchecked := client.With(WithLanguageValidation(false))
+	if _, err := unchecked.Translate(context.Background(), "Hello", language.English, language.French); err != nil {
+		t.Errorf("validation disabled: unexpected error: %v", err)
+	}
+}
+
+func TestLanguageValidationListUnavailable(t *testing.T) {
+	languagesSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer languagesSrv.Close()
+	translateSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, capturedHello)
+	}))
+	defer translateSrv.Close()
+
+	var warnings []error
+	client := NewClient(
+		WithTranslateURL(translateSrv.URL),
+		WithLanguagesURL(languagesSrv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }),
+	)
+	result, err := client.Translate(context.Background(), "Hello", language.English, language.German)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Translation != "Hallo" {
+		t.Errorf("got translation %q, want %q", result.Translation, "Hallo")
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrServer) {
+		t.Errorf("got warnings %v, want the failed listing", warnings)
+	}
+}
@@ -0,0 +1,169 @@
+package gtranslate
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/text/language"
+)
+
+// LibreTranslateTranslator translates with a LibreTranslate server
+type LibreTranslateTranslator struct {
+	client  *Client
+	apiKey  string
+	baseURL string
+}
+
+// Make sure the LibreTranslate backend implements the interface
+var _ Translator = (*LibreTranslateTranslator)(nil)
+
+// NewLibreTranslateTranslator creates a LibreTranslate backend for the server at baseURL.
+// The API key may be empty for servers that do not require one.
+func NewLibreTranslateTranslator(baseURL, apiKey string, opts ...Option) *LibreTranslateTranslator {
+	client, baseURL := newBackendClient(baseURL, opts)
+	return &LibreTranslateTranslator{
+		client:  client,
+		apiKey:  apiKey,
+		baseURL: baseURL,
+	}
+}
+
+// Name returns the identifier of the LibreTranslate backend
+func (t *LibreTranslateTranslator) Name() string {
+	return "libretranslate"
+}
+
+// libreDetection is a language detected by LibreTranslate, with a confidence between 0 and 100
+type libreDetection struct {
+	Confidence float64 `json:"confidence"`
+	Language   string  `json:"language"`
+}
+
+// Translate translates a single piece of content as a batch of one item
+func (t *LibreTranslateTranslator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	return translateAsBatch(ctx, t, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch translates several pieces of content in a single request
+func (t *LibreTranslateTranslator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	sourceLanguage, targetLanguage, err := t.client.resolveLanguages(sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	source, err := t.languageCode(sourceLanguage)
+	if err != nil {
+		return nil, err
+	}
+	target, err := t.languageCode(targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	request := map[string]interface{}{
+		"q":      contents,
+		"source": source,
+		"target": target,
+		"format": "text",
+	}
+	if t.apiKey != "" {
+		request["api_key"] = t.apiKey
+	}
+
+	var response struct {
+		TranslatedText   []string         `json:"translatedText"`
+		DetectedLanguage []libreDetection `json:"detectedLanguage"`
+	}
+	err = t.client.doJSON(ctx, "POST", t.baseURL+"/translate", nil, request, &response, countCharacters(contents))
+	if err != nil {
+		return nil, err
+	}
+	if len(response.TranslatedText) != len(contents) {
+		return nil, fmt.Errorf("%w: expected %d translations, got %d", ErrMalformedResponse, len(contents), len(response.TranslatedText))
+	}
+
+	results := make([]BatchResult, len(contents))
+	for i, translation := range response.TranslatedText {
+		results[i] = BatchResult{
+			Index:          i,
+			Content:        contents[i],
+			Translation:    translation,
+			SourceLanguage: sourceLanguage,
+		}
+		if i < len(response.DetectedLanguage) {
+			if results[i].SourceLanguage, err = t.client.codecTag(LibreTranslateLanguageCodec, response.DetectedLanguage[i].Language); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// Detect detects the language of the content
+func (t *LibreTranslateTranslator) Detect(ctx context.Context, content string) (*Detection, error) {
+	request := map[string]interface{}{"q": content}
+	if t.apiKey != "" {
+		request["api_key"] = t.apiKey
+	}
+
+	var response []libreDetection
+	err := t.client.doJSON(ctx, "POST", t.baseURL+"/detect", nil, request, &response, countCharacters([]string{content}))
+	if err != nil {
+		return nil, err
+	}
+	if len(response) == 0 {
+		return nil, fmt.Errorf("%w: no detected language", ErrMalformedResponse)
+	}
+
+	tag, err := t.client.codecTag(LibreTranslateLanguageCodec, response[0].Language)
+	if err != nil {
+		return nil, err
+	}
+	return &Detection{Language: tag, Confidence: response[0].Confidence / 100}, nil
+}
+
+// SupportedLanguages lists the languages supported by the server. A language is listed as a target
+// when any other language can be translated to it. LibreTranslate only provides English names,
+// so the display language is ignored.
+func (t *LibreTranslateTranslator) SupportedLanguages(ctx context.Context, _ language.Tag) (*Languages, error) {
+	var response []struct {
+		Code    string   `json:"code"`
+		Name    string   `json:"name"`
+		Targets []string `json:"targets"`
+	}
+	err := t.client.doJSON(ctx, "GET", t.baseURL+"/languages", nil, nil, &response, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := &Languages{}
+	names := make(map[string]string, len(response))
+	targets := make(map[string]bool)
+	for _, l := range response {
+		names[l.Code] = l.Name
+		languages.Source = append(languages.Source, Language{Tag: listedTag(LibreTranslateLanguageCodec, l.Code), Code: l.Code, Name: l.Name})
+		for _, target := range l.Targets {
+			targets[target] = true
+		}
+	}
+	for code := range targets {
+		languages.Target = append(languages.Target, Language{Tag: listedTag(LibreTranslateLanguageCodec, code), Code: code, Name: names[code]})
+	}
+	sortLanguages(languages.Target)
+	return languages, nil
+}
+
+// LibreTranslateLanguageCodec maps tags to the codes of LibreTranslate, which distinguishes the scripts
+// of Chinese and Brazilian Portuguese. Servers may offer only some of these languages.
+var LibreTranslateLanguageCodec = NewLanguageCodec("libretranslate", []string{
+	"ar", "az", "bg", "bn", "ca", "cs", "da", "de", "el", "en", "eo", "es", "et", "eu", "fa", "fi", "fr",
+	"ga", "gl", "he", "hi", "hu", "id", "it", "ja", "ko", "ky", "lt", "lv", "ms", "nb", "nl", "pl", "pt",
+	"pt-BR", "ro", "ru", "sk", "sl", "sq", "sr", "sv", "th", "tl", "tr", "uk", "ur", "vi", "zh-Hans", "zh-Hant",
+}, map[string]string{
+	"no": "nb",
+})
+
+// languageCode returns the LibreTranslate code of a language, "auto" for automatic detection
+func (t *LibreTranslateTranslator) languageCode(tag language.Tag) (string, error) {
+	if tag.IsRoot() {
+		return "auto", nil
+	}
+	return t.client.codecCode(LibreTranslateLanguageCodec, tag)
+}
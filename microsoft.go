@@ -0,0 +1,184 @@
+package gtranslate
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/text/language"
+	"net/http"
+	"net/url"
+)
+
+// microsoftTranslatorAPI is the global endpoint of the Microsoft Translator API
+const microsoftTranslatorAPI = "https://api.cognitive.microsofttranslator.com"
+
+// MicrosoftTranslator translates with the Microsoft Translator API
+type MicrosoftTranslator struct {
+	client  *Client
+	key     string
+	region  string
+	baseURL string
+}
+
+// Make sure the Microsoft backend implements the interface
+var _ Translator = (*MicrosoftTranslator)(nil)
+
+// NewMicrosoftTranslator creates a Microsoft Translator backend authenticated with the subscription key.
+// The region may be empty for global resources.
+func NewMicrosoftTranslator(key, region string, opts ...Option) *MicrosoftTranslator {
+	client, baseURL := newBackendClient(microsoftTranslatorAPI, opts)
+	return &MicrosoftTranslator{
+		client:  client,
+		key:     key,
+		region:  region,
+		baseURL: baseURL,
+	}
+}
+
+// Name returns the identifier of the Microsoft backend
+func (t *MicrosoftTranslator) Name() string {
+	return "microsoft"
+}
+
+// MicrosoftLanguageCodec maps tags to the codes of Microsoft Translator, which distinguishes the scripts
+// of Chinese, Serbian, Mongolian and Inuktitut, and a few regional variants
+var MicrosoftLanguageCodec = NewLanguageCodec("microsoft", []string{
+	"af", "am", "ar", "as", "az", "ba", "bg", "bho", "bn", "bo", "brx", "bs", "ca", "cs", "cy", "da", "de",
+	"doi", "dsb", "dv", "el", "en", "es", "et", "eu", "fa", "fi", "fil", "fj", "fo", "fr", "fr-CA", "ga",
+	"gl", "gom", "gu", "ha", "he", "hi", "hne", "hr", "hsb", "ht", "hu", "hy", "id", "ig", "ikt", "is", "it",
+	"iu", "iu-Latn", "ja", "ka", "kk", "km", "kmr", "kn", "ko", "ks", "ku", "ky", "ln", "lo", "lt", "lug",
+	"lv", "lzh", "mai", "mg", "mi", "mk", "ml", "mn-Cyrl", "mn-Mong", "mni", "mr", "ms", "mt", "mww", "my",
+	"nb", "ne", "nl", "nso", "nya", "or", "otq", "pa", "pl", "prs", "ps", "pt", "pt-PT", "ro", "ru", "run",
+	"rw", "sd", "si", "sk", "sl", "sm", "sn", "so", "sq", "sr-Cyrl", "sr-Latn", "st", "sv", "sw", "ta", "te",
+	"th", "ti", "tk", "tlh-Latn", "tlh-Piqd", "tn", "to", "tr", "tt", "ty", "ug", "uk", "ur", "uz", "vi",
+	"xh", "yo", "yua", "yue", "zh-Hans", "zh-Hant", "zu",
+}, map[string]string{
+	// Dari is the Persian of Afghanistan, and Klingon defaults to the Latin script
+	"fa-AF": "prs",
+	"tlh":   "tlh-Latn",
+	"no":    "nb",
+})
+
+// microsoftText is the request body item of the Microsoft Translator API
+type microsoftText struct {
+	Text string `json:"Text"`
+}
+
+// microsoftDetection is a language detected by the Microsoft Translator API
+type microsoftDetection struct {
+	Language string  `json:"language"`
+	Score    float64 `json:"score"`
+}
+
+// Translate translates a single piece of content as a batch of one item
+func (t *MicrosoftTranslator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	return translateAsBatch(ctx, t, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch translates several pieces of content in a single request
+func (t *MicrosoftTranslator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	sourceLanguage, targetLanguage, err := t.client.resolveLanguages(sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	to, err := t.client.codecCode(MicrosoftLanguageCodec, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	parameters := url.Values{"api-version": {"3.0"}, "to": {to}}
+	if !sourceLanguage.IsRoot() {
+		from, err := t.client.codecCode(MicrosoftLanguageCodec, sourceLanguage)
+		if err != nil {
+			return nil, err
+		}
+		parameters.Set("from", from)
+	}
+
+	var response []struct {
+		DetectedLanguage *microsoftDetection `json:"detectedLanguage"`
+		Translations     []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	err = t.client.doJSON(ctx, "POST", t.baseURL+"/translate?"+parameters.Encode(), t.header(), microsoftTexts(contents), &response, countCharacters(contents))
+	if err != nil {
+		return nil, err
+	}
+	if len(response) != len(contents) {
+		return nil, fmt.Errorf("%w: expected %d translations, got %d", ErrMalformedResponse, len(contents), len(response))
+	}
+
+	results := make([]BatchResult, len(contents))
+	for i, item := range response {
+		results[i] = BatchResult{Index: i, Content: contents[i], SourceLanguage: sourceLanguage}
+		if len(item.Translations) > 0 {
+			results[i].Translation = item.Translations[0].Text
+		}
+		if item.DetectedLanguage != nil {
+			if results[i].SourceLanguage, err = t.client.codecTag(MicrosoftLanguageCodec, item.DetectedLanguage.Language); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// Detect detects the language of the content
+func (t *MicrosoftTranslator) Detect(ctx context.Context, content string) (*Detection, error) {
+	var response []microsoftDetection
+	err := t.client.doJSON(ctx, "POST", t.baseURL+"/detect?api-version=3.0", t.header(), microsoftTexts([]string{content}), &response, countCharacters([]string{content}))
+	if err != nil {
+		return nil, err
+	}
+	if len(response) == 0 {
+		return nil, fmt.Errorf("%w: no detected language", ErrMalformedResponse)
+	}
+	tag, err := t.client.codecTag(MicrosoftLanguageCodec, response[0].Language)
+	if err != nil {
+		return nil, err
+	}
+	return &Detection{Language: tag, Confidence: response[0].Score}, nil
+}
+
+// SupportedLanguages lists the languages available for translation, named in the display language.
+// Microsoft Translator can translate between any two of them, so the source and target lists are the same.
+func (t *MicrosoftTranslator) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	header := http.Header{}
+	if !displayLanguage.IsRoot() {
+		header.Set("Accept-Language", displayLanguage.String())
+	}
+
+	var response struct {
+		Translation map[string]struct {
+			Name string `json:"name"`
+		} `json:"translation"`
+	}
+	err := t.client.doJSON(ctx, "GET", t.baseURL+"/languages?api-version=3.0&scope=translation", header, nil, &response, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	languages := make([]Language, 0, len(response.Translation))
+	for code, l := range response.Translation {
+		languages = append(languages, Language{Tag: listedTag(MicrosoftLanguageCodec, code), Code: code, Name: l.Name})
+	}
+	sortLanguages(languages)
+	return &Languages{Source: languages, Target: languages}, nil
+}
+
+// header returns the authentication headers of Microsoft Translator requests
+func (t *MicrosoftTranslator) header() http.Header {
+	header := http.Header{"Ocp-Apim-Subscription-Key": {t.key}}
+	if t.region != "" {
+		header.Set("Ocp-Apim-Subscription-Region", t.region)
+	}
+	return header
+}
+
+// microsoftTexts converts contents into the request body of the Microsoft Translator API
+func microsoftTexts(contents []string) []microsoftText {
+	texts := make([]microsoftText, len(contents))
+	for i, content := range contents {
+		texts[i] = microsoftText{Text: content}
+	}
+	return texts
+}
@@ -6,31 +6,48 @@ import (
 
 // Sentence represents a sentence's content, its translation, pronunciation, and frequency.
 type Sentence struct {
-	Content       string  // The original content of the sentence.
-	Translation   string  // The translated content of the sentence.
-	Pronunciation string  // The pronunciation of the sentence in the target language.
-	Frequency     float64 // The frequency of the sentence appearing in the corpus of data.
+	Content               string  // The original content of the sentence.
+	Translation           string  // The translated content of the sentence.
+	Pronunciation         string  // The pronunciation of the sentence in the target language, the same as TargetTransliteration.
+	SourceTransliteration string  // The romanization of the original content of the sentence.
+	TargetTransliteration string  // The romanization of the translated content of the sentence.
+	Frequency             float64 // The frequency of the sentence appearing in the corpus of data.
 }
 
 // Equivalent represents an equivalent word in the target language.
 type Equivalent struct {
-	Content     string   // The original content of the word.
-	Equivalents []string // The equivalent words in the target language.
-	Frequency   float64  // The frequency of the equivalent words appearing in the corpus of data.
+	Content       string        // The original content of the word.
+	Equivalents   []string      // The reverse translations of the word back into the source language.
+	Frequency     float64       // The frequency of the equivalent words appearing in the corpus of data.
+	FrequencyTier FrequencyTier // The frequency of the word grouped as common, uncommon or rare, or unknown without a score.
+	Article       string        // The definite article of the word, for languages with grammatical gender.
+	Gender        Gender        // The grammatical gender given by the article.
 }
 
 // WordTranslation represents a word translation along with its equivalent in the target language.
 type WordTranslation struct {
 	PartsOfSentence string       // The part of speech for the word.
+	PartOfSpeech    PartOfSpeech // The part of speech for the word, independent of the display language.
+	BaseForm        string       // The base form of the word the entry is for, such as the infinitive of a verb.
 	Frequency       float64      // The frequency of the word appearing in the corpus of data.
 	Translations    []string     // The translations of the word.
 	Equivalents     []Equivalent // The equivalent translations of the word.
 }
 
+// TextSpan represents the position of a part of a text, in bytes and in runes.
+type TextSpan struct {
+	Start     int // The byte offset of the start of the part.
+	End       int // The byte offset just after the end of the part.
+	RuneStart int // The rune offset of the start of the part.
+	RuneEnd   int // The rune offset just after the end of the part.
+}
+
 // AlternateTranslation represents an alternate translation for the original content.
 type AlternateTranslation struct {
 	Content      string        // The original content of the translation.
 	Translations []Translation // The alternate translations.
+	SourceSpans  []TextSpan    // The positions of the segment in the Content of the result, usually a single span.
+	TargetSpans  []TextSpan    // The position of the segment's translation in the Translation of the result, empty if not found.
 }
 
 // Translation represents a translation and its type.
@@ -66,16 +83,61 @@ type WordDefinition struct {
 	Definitions     []Definition // The definitions of the word.
 }
 
+// RelatedWordGroup represents a list of words related to the original content, shown as "see also" by Google Translate.
+type RelatedWordGroup struct {
+	Words []string // The related words.
+}
+
 // TranslationResult represents the full result of a translation request.
 type TranslationResult struct {
-	Content               string                 // The original content to translate.
-	Translation           string                 // The translated content.
-	Pronunciation         string                 // The pronunciation of the translated content.
-	TranslatedSentences   []Sentence             // The sentences after translation.
-	WordTranslations      []WordTranslation      // The translations of the words in the content.
-	SourceLanguage        language.Tag           // The source language of the original content.
-	AlternateTranslations []AlternateTranslation // The alternate translations for the original content.
-	WordSynonyms          []WordSynonym          // The synonyms of the words in the content.
-	WordDefinitions       []WordDefinition       // The definitions of the words in the content.
-	WordExamples          []string               // The examples of the words in the content.
+	Content                     string                 // The original content to translate.
+	Translation                 string                 // The translated content.
+	Pronunciation               string                 // The pronunciation of the translated content, the same as TargetTransliteration.
+	SourceTransliteration       string                 // The romanization of the original content.
+	TargetTransliteration       string                 // The romanization of the translated content.
+	TranslatedSentences         []Sentence             // The sentences after translation.
+	WordTranslations            []WordTranslation      // The translations of the words in the content.
+	SourceLanguage              language.Tag           // The source language of the original content.
+	SourceLanguageCode          string                 // The provider code of the source language, as found in the response.
+	SourceLanguageConfidence    float64                // The confidence of the detected source language between 0 and 1.
+	Confidence                  float64                // The confidence of the translation between 0 and 1.
+	CorrectedContent            string                 // The spelling-corrected original content, empty when no correction is suggested.
+	CorrectedContentHTML        string                 // The corrected content with the changed spans marked as <b><i>…</i></b>.
+	Autocorrected               bool                   // A boolean value indicating if the correction was applied before translating.
+	SuggestedSourceLanguage     language.Tag           // The language the content seems to be written in, when it differs from the source language.
+	SuggestedSourceLanguageCode string                 // The provider code of the suggested source language.
+	AlternateTranslations       []AlternateTranslation // The alternate translations for the original content.
+	WordSynonyms                []WordSynonym          // The synonyms of the words in the content.
+	WordDefinitions             []WordDefinition       // The definitions of the words in the content.
+	WordExamples                []string               // The examples of the words in the content.
+	RelatedWords                []RelatedWordGroup     // The related words of the content.
+	Provider                    string                 // The name of the backend that served the result, set by FailoverTranslator.
+}
+
+// BatchResult represents the translation of a single item of a batch request.
+type BatchResult struct {
+	Index          int          // The position of the item in the batch.
+	Content        string       // The original content to translate.
+	Translation    string       // The translated content.
+	SourceLanguage language.Tag // The source language of the item, detected when not provided.
+	Provider       string       // The name of the backend that served the result, set by FailoverTranslator.
+}
+
+// Detection represents the detected language of a piece of content.
+type Detection struct {
+	Language   language.Tag // The detected language.
+	Confidence float64      // The confidence of the detection between 0 and 1, zero when not reported.
+}
+
+// Language represents a language supported by a translation backend.
+type Language struct {
+	Tag  language.Tag // The language tag, the root tag if the code is not a valid BCP 47 tag.
+	Code string       // The code used by the backend for the language.
+	Name string       // The display name of the language.
+}
+
+// Languages represents the source and target languages supported by a translation backend.
+type Languages struct {
+	Source []Language // The languages that can be translated from.
+	Target []Language // The languages that can be translated to.
 }
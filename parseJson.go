@@ -3,249 +3,702 @@ package gtranslate
 import (
 	"encoding/json"
 	"errors"
-	"golang.org/x/text/language"
+	"fmt"
+	"strings"
 )
 
-// safeGetBool is a utility function to safely retrieve a boolean value from a slice of interface{} at the given index
-func safeGetBool(slice []interface{}, index int) bool {
-	if len(slice) > index {
-		if str, isValid := slice[index].(bool); isValid {
-			return str
+// Define the positions of the rows of a response from the single translation endpoint
+const (
+	rowSentences      = 0  // The translated sentences followed by the transliteration row.
+	rowDictionary     = 1  // The dictionary entries of single words.
+	rowSourceLanguage = 2  // The code of the source language.
+	rowAlternates     = 5  // The alternate translations of each segment.
+	rowConfidence     = 6  // The confidence of the translation.
+	rowCorrection     = 7  // The spelling correction of the content.
+	rowDetection      = 8  // The detected source languages and their confidence.
+	rowSynonyms       = 11 // The synonyms of single words.
+	rowDefinitions    = 12 // The definitions of single words.
+	rowExamples       = 13 // The usage examples of single words.
+	rowRelatedWords   = 14 // The related words of single words.
+)
+
+// rawTranslation is the decoded response of the single translation endpoint
+type rawTranslation struct {
+	decoded
+	Sentences      []rawSentence
+	Dictionary     []rawDictionaryEntry
+	SourceLanguage string
+	Alternates     []rawAlternate
+	Confidence     float64
+	Correction     *rawCorrection
+	Detection      *rawDetection
+	Synonyms       []rawSynonymEntry
+	Definitions    []rawDefinitionEntry
+	Examples       []string
+	RelatedWords   [][]string
+}
+
+// UnmarshalJSON decodes the positional array of the response
+func (r *rawTranslation) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+
+	p.each(rowSentences, func(l *positions, j int) {
+		var sentence rawSentence
+		if l.decode(j, &sentence, "array") {
+			r.Sentences = append(r.Sentences, sentence)
+		}
+	})
+	p.each(rowDictionary, func(l *positions, j int) {
+		var entry rawDictionaryEntry
+		if l.decode(j, &entry, "array") {
+			r.Dictionary = append(r.Dictionary, entry)
 		}
+	})
+	r.SourceLanguage = p.string(rowSourceLanguage)
+	p.each(rowAlternates, func(l *positions, j int) {
+		var alternate rawAlternate
+		if l.decode(j, &alternate, "array") {
+			r.Alternates = append(r.Alternates, alternate)
+		}
+	})
+	r.Confidence = p.number(rowConfidence)
+
+	var correction rawCorrection
+	if p.decode(rowCorrection, &correction, "array") {
+		r.Correction = &correction
+	}
+	var detection rawDetection
+	if p.decode(rowDetection, &detection, "array") {
+		r.Detection = &detection
 	}
-	return false
-}
 
-// safeGetString is a utility function to safely retrieve a string value from a slice of interface{} at the given index
-func safeGetString(slice []interface{}, index int) string {
-	if len(slice) > index {
-		if str, isValid := slice[index].(string); isValid {
-			return str
+	p.each(rowSynonyms, func(l *positions, j int) {
+		var entry rawSynonymEntry
+		if l.decode(j, &entry, "array") {
+			r.Synonyms = append(r.Synonyms, entry)
+		}
+	})
+	p.each(rowDefinitions, func(l *positions, j int) {
+		var entry rawDefinitionEntry
+		if l.decode(j, &entry, "array") {
+			r.Definitions = append(r.Definitions, entry)
+		}
+	})
+	p.each(rowExamples, func(l *positions, j int) {
+		l.each(j, func(m *positions, k int) {
+			var example rawExample
+			if m.decode(k, &example, "array") {
+				r.Examples = append(r.Examples, example.Text)
+			}
+		})
+	})
+	p.each(rowRelatedWords, func(l *positions, j int) {
+		if words := l.strings(j); len(words) > 0 {
+			r.RelatedWords = append(r.RelatedWords, words)
 		}
+	})
+
+	r.diagnostics = p.finish()
+	return nil
+}
+
+// rawSentence is a translated sentence, or the trailing
+// [null, null, target transliteration, source transliteration] row
+type rawSentence struct {
+	decoded
+	Translation           string
+	Content               string
+	TargetTransliteration string
+	SourceTransliteration string
+	Frequency             float64
+	IsTransliteration     bool
+}
+
+// UnmarshalJSON decodes the positional array of the sentence
+func (s *rawSentence) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
 	}
-	return ""
+	hasTranslation := p.decode(0, &s.Translation, "string")
+	hasContent := p.decode(1, &s.Content, "string")
+	s.TargetTransliteration = p.string(2)
+	s.SourceTransliteration = p.string(3)
+	s.Frequency = p.number(4)
+	p.ignore(5, 6, 7, 8)
+	s.IsTransliteration = !hasTranslation && !hasContent && p.len() > 2
+	s.diagnostics = p.finish()
+	return nil
+}
+
+// rawDictionaryEntry is the dictionary entry of a word for a part of speech
+type rawDictionaryEntry struct {
+	decoded
+	PartOfSpeechName string
+	Translations     []string
+	Equivalents      []rawEquivalent
+	BaseForm         string
+	PartOfSpeech     float64
 }
 
-// safeGetFloat64 is a utility function to safely retrieve a float64 value from a slice of interface{} at the given index
-func safeGetFloat64(slice []interface{}, index int) float64 {
-	if len(slice) > index {
-		if f, isValid := slice[index].(float64); isValid {
-			return f
+// UnmarshalJSON decodes the positional array of the dictionary entry
+func (e *rawDictionaryEntry) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	e.PartOfSpeechName = p.string(0)
+	e.Translations = p.strings(1)
+	p.each(2, func(l *positions, j int) {
+		var equivalent rawEquivalent
+		if l.decode(j, &equivalent, "array") {
+			e.Equivalents = append(e.Equivalents, equivalent)
 		}
+	})
+	e.BaseForm = p.string(3)
+	e.PartOfSpeech = p.number(4)
+	e.diagnostics = p.finish()
+	return nil
+}
+
+// rawEquivalent is a sense of a dictionary entry
+type rawEquivalent struct {
+	decoded
+	Content             string
+	ReverseTranslations []string
+	Frequency           float64
+	Article             string
+}
+
+// UnmarshalJSON decodes the positional array of the sense
+func (e *rawEquivalent) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
 	}
-	return 0.0
+	e.Content = p.string(0)
+	e.ReverseTranslations = p.strings(1)
+	p.ignore(2)
+	e.Frequency = p.number(3)
+	e.Article = p.string(4)
+	e.diagnostics = p.finish()
+	return nil
 }
 
-// safeGetInterfaceSlice is a utility function to safely retrieve a slice of interface{} from a slice of interface{} at the given index
-func safeGetInterfaceSlice(slice []interface{}, index int) []interface{} {
-	if len(slice) > index {
-		if intfSlice, isValid := slice[index].([]interface{}); isValid {
-			return intfSlice
+// rawAlternate is a segment of the content with its alternate translations
+type rawAlternate struct {
+	decoded
+	Content      string
+	Alternatives []rawAlternative
+	Spans        [][2]int
+	Sentence     string
+}
+
+// UnmarshalJSON decodes the positional array of the segment
+func (a *rawAlternate) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	a.Content = p.string(0)
+	p.ignore(1)
+	p.each(2, func(l *positions, j int) {
+		var alternative rawAlternative
+		if l.decode(j, &alternative, "array") {
+			a.Alternatives = append(a.Alternatives, alternative)
+		}
+	})
+	p.each(3, func(l *positions, j int) {
+		if span := l.numbers(j); len(span) == 2 {
+			a.Spans = append(a.Spans, [2]int{int(span[0]), int(span[1])})
 		}
+	})
+	a.Sentence = p.string(4)
+	p.ignore(5, 6)
+	a.diagnostics = p.finish()
+	return nil
+}
+
+// rawAlternative is an alternate translation of a segment
+type rawAlternative struct {
+	decoded
+	Translation string
+	IsCommon    bool
+	IsInformal  bool
+}
+
+// UnmarshalJSON decodes the positional array of the alternate translation
+func (a *rawAlternative) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
 	}
+	a.Translation = p.string(0)
+	p.ignore(1)
+	a.IsCommon = p.boolean(2)
+	a.IsInformal = p.boolean(3)
+	p.ignore(4)
+	a.diagnostics = p.finish()
 	return nil
 }
 
-// parseOverallTranslation parses the overall translation result from Google Translate
-func parseOverallTranslation(row []interface{}, result *TranslationResult) {
-	result.TranslatedSentences = make([]Sentence, len(row))
-	for i, rowItem := range row {
-		if rowData, isValid := rowItem.([]interface{}); isValid {
-			translation := safeGetString(rowData, 0)
-			content := safeGetString(rowData, 1)
-			pronunciation := safeGetString(rowData, 3)
-			frequency := safeGetFloat64(rowData, 4)
+// rawCorrection is the spelling correction of the content
+type rawCorrection struct {
+	decoded
+	HTML          string
+	Text          string
+	Autocorrected bool
+}
+
+// UnmarshalJSON decodes the positional array of the correction
+func (c *rawCorrection) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	c.HTML = p.string(0)
+	c.Text = p.string(1)
+	p.ignore(2, 3, 4)
+	c.Autocorrected = p.boolean(5)
+	c.diagnostics = p.finish()
+	return nil
+}
 
-			result.Translation += translation
-			result.TranslatedSentences[i].Translation = translation
+// rawDetection is the list of detected source languages with their confidence
+type rawDetection struct {
+	decoded
+	Languages   []string
+	Confidences []float64
+}
 
-			result.Content += content
-			result.TranslatedSentences[i].Content = content
+// UnmarshalJSON decodes the positional array of the detection
+func (d *rawDetection) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	d.Languages = p.strings(0)
+	p.ignore(1)
+	d.Confidences = p.numbers(2)
+	p.ignore(3)
+	d.diagnostics = p.finish()
+	return nil
+}
 
-			result.Pronunciation = pronunciation
-			result.TranslatedSentences[i].Pronunciation = pronunciation
+// rawSynonymEntry is the list of synonyms of a word for a part of speech
+type rawSynonymEntry struct {
+	decoded
+	PartOfSpeechName string
+	Sets             []rawSynonymSet
+	Content          string
+	Frequency        float64
+}
 
-			result.TranslatedSentences[i].Frequency = frequency
+// UnmarshalJSON decodes the positional array of the synonym entry
+func (e *rawSynonymEntry) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	e.PartOfSpeechName = p.string(0)
+	p.each(1, func(l *positions, j int) {
+		var set rawSynonymSet
+		if l.decode(j, &set, "array") {
+			e.Sets = append(e.Sets, set)
 		}
+	})
+	e.Content = p.string(2)
+	e.Frequency = p.number(3)
+	e.diagnostics = p.finish()
+	return nil
+}
+
+// rawSynonymSet is a group of synonyms sharing a category
+type rawSynonymSet struct {
+	decoded
+	Synonyms []string
+	Category string
+}
+
+// UnmarshalJSON decodes the positional array of the synonym group
+func (s *rawSynonymSet) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	s.Synonyms = p.strings(0)
+	p.ignore(1)
+	categoryList := p.list(2)
+	if categories := categoryList.strings(0); len(categories) > 0 {
+		s.Category = categories[0]
 	}
+	categoryList.finish()
+	s.diagnostics = p.finish()
+	return nil
 }
 
-// parseDetailedTranslation parses the detailed translation result for each word from Google Translate
-func parseDetailedTranslation(row []interface{}, result *TranslationResult) {
-	result.WordTranslations = make([]WordTranslation, len(row))
-	for i, rowObj := range row {
-		if rowData, isValid := rowObj.([]interface{}); isValid {
-			partOfSentence := safeGetString(rowData, 0)
-			translations := safeGetInterfaceSlice(rowData, 1)
-			equivalents := safeGetInterfaceSlice(rowData, 2)
-			frequency := safeGetFloat64(rowData, 4)
-
-			result.WordTranslations[i].PartsOfSentence = partOfSentence
-
-			for _, translation := range translations {
-				if transStr, isString := translation.(string); isString {
-					result.WordTranslations[i].Translations = append(result.WordTranslations[i].Translations, transStr)
-				}
-			}
+// rawDefinitionEntry is the list of definitions of a word for a part of speech
+type rawDefinitionEntry struct {
+	decoded
+	PartOfSpeechName string
+	Definitions      []rawDefinition
+}
 
-			for _, equivalent := range equivalents {
-				if equivArr, isArray := equivalent.([]interface{}); isArray {
-					var equivalentDetail Equivalent
-					equivalentDetail.Content = safeGetString(equivArr, 0)
+// UnmarshalJSON decodes the positional array of the definition entry
+func (e *rawDefinitionEntry) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	e.PartOfSpeechName = p.string(0)
+	p.each(1, func(l *positions, j int) {
+		var definition rawDefinition
+		if l.decode(j, &definition, "array") {
+			e.Definitions = append(e.Definitions, definition)
+		}
+	})
+	p.ignore(2, 3)
+	e.diagnostics = p.finish()
+	return nil
+}
 
-					if equivalentWords, isWordsArray := equivArr[1].([]interface{}); isWordsArray {
-						for _, equivWord := range equivalentWords {
-							equivalentDetail.Equivalents = append(equivalentDetail.Equivalents, equivWord.(string))
-						}
-					}
+// rawDefinition is a definition of a word with an example
+type rawDefinition struct {
+	decoded
+	Definition string
+	Example    string
+}
 
-					equivalentDetail.Frequency = safeGetFloat64(equivArr, 3)
-					result.WordTranslations[i].Equivalents = append(result.WordTranslations[i].Equivalents, equivalentDetail)
-				}
+// UnmarshalJSON decodes the positional array of the definition
+func (d *rawDefinition) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	d.Definition = p.string(0)
+	p.ignore(1)
+	d.Example = p.string(2)
+	p.ignore(3)
+	d.diagnostics = p.finish()
+	return nil
+}
+
+// rawExample is a usage example of a word
+type rawExample struct {
+	decoded
+	Text string
+}
+
+// UnmarshalJSON decodes the positional array of the example
+func (e *rawExample) UnmarshalJSON(data []byte) error {
+	p, err := newPositions(data)
+	if err != nil {
+		return err
+	}
+	e.Text = p.string(0)
+	p.ignore(1, 2, 3, 4, 5)
+	e.diagnostics = p.finish()
+	return nil
+}
+
+// parseOverallTranslation parses the overall translation result from Google Translate
+func parseOverallTranslation(sentences []rawSentence, result *TranslationResult, sections Section) {
+	result.TranslatedSentences = make([]Sentence, 0, len(sentences))
+	for _, rawSentence := range sentences {
+		// The transliteration of the whole text comes after the sentences
+		if rawSentence.IsTransliteration {
+			if sections.has(SectionTransliteration) {
+				result.TargetTransliteration = rawSentence.TargetTransliteration
+				result.SourceTransliteration = rawSentence.SourceTransliteration
 			}
+			continue
+		}
+
+		var sentence Sentence
+		if sections.has(SectionTranslation) {
+			sentence.Translation = rawSentence.Translation
+			sentence.Content = rawSentence.Content
+			sentence.Frequency = rawSentence.Frequency
+
+			result.Translation += sentence.Translation
+			result.Content += sentence.Content
+		}
 
-			result.WordTranslations[i].Frequency = frequency
+		if sections.has(SectionTransliteration) {
+			sentence.TargetTransliteration = rawSentence.TargetTransliteration
+			sentence.SourceTransliteration = rawSentence.SourceTransliteration
+			sentence.Pronunciation = sentence.TargetTransliteration
 		}
+		result.TranslatedSentences = append(result.TranslatedSentences, sentence)
+	}
+
+	if sections.has(SectionTransliteration) {
+		joinSentenceTransliterations(result)
 	}
 }
 
-// parseAlternateTranslations parses the alternate translations for the text from Google Translate
-func parseAlternateTranslations(row []interface{}, result *TranslationResult) {
-	for _, item := range row {
-		if rowData, isValid := item.([]interface{}); isValid {
-			altTrans := AlternateTranslation{
-				Content: safeGetString(rowData, 0),
-			}
-			altTransList := safeGetInterfaceSlice(rowData, 2)
-			for _, alt := range altTransList {
-				if transDetails, isValid := alt.([]interface{}); isValid {
-					translation := Translation{
-						Translation: safeGetString(transDetails, 0),
-						IsCommon:    safeGetBool(transDetails, 2),
-						IsInformal:  safeGetBool(transDetails, 3),
-					}
-					altTrans.Translations = append(altTrans.Translations, translation)
-				}
-			}
-			result.AlternateTranslations = append(result.AlternateTranslations, altTrans)
+// joinSentenceTransliterations completes the transliterations of the result from those of its sentences.
+// Without a trailing transliteration row the sentence transliterations are concatenated,
+// and a single sentence takes the transliteration of the whole text.
+func joinSentenceTransliterations(result *TranslationResult) {
+	if len(result.TranslatedSentences) == 1 {
+		sentence := &result.TranslatedSentences[0]
+		if sentence.TargetTransliteration == "" {
+			sentence.TargetTransliteration = result.TargetTransliteration
+			sentence.Pronunciation = result.TargetTransliteration
 		}
+		if sentence.SourceTransliteration == "" {
+			sentence.SourceTransliteration = result.SourceTransliteration
+		}
+	}
+
+	var source, target strings.Builder
+	for _, sentence := range result.TranslatedSentences {
+		source.WriteString(sentence.SourceTransliteration)
+		target.WriteString(sentence.TargetTransliteration)
+	}
+	if result.SourceTransliteration == "" {
+		result.SourceTransliteration = source.String()
+	}
+	if result.TargetTransliteration == "" {
+		result.TargetTransliteration = target.String()
 	}
+	result.Pronunciation = result.TargetTransliteration
 }
 
-// parseWordSynonyms parses the synonyms for the words in the text from Google Translate
-func parseWordSynonyms(row []interface{}, translation *TranslationResult) {
-	for _, row := range row {
-		if rowData, isValid := row.([]interface{}); isValid {
-
-			wordSynonym := WordSynonym{
-				PartsOfSentence: safeGetString(rowData, 0),
-				Contents:        safeGetString(rowData, 2),
-				Frequency:       safeGetFloat64(rowData, 3),
+// parseDetailedTranslation parses the detailed translation result for each word from Google Translate
+func parseDetailedTranslation(entries []rawDictionaryEntry, result *TranslationResult) {
+	result.WordTranslations = make([]WordTranslation, len(entries))
+	for i, entry := range entries {
+		wordTranslation := &result.WordTranslations[i]
+		wordTranslation.PartsOfSentence = entry.PartOfSpeechName
+		wordTranslation.PartOfSpeech = PartOfSpeech(entry.PartOfSpeech)
+		wordTranslation.BaseForm = entry.BaseForm
+		wordTranslation.Translations = entry.Translations
+
+		for _, equivalent := range entry.Equivalents {
+			equivalentDetail := Equivalent{
+				Content:     equivalent.Content,
+				Equivalents: equivalent.ReverseTranslations,
+				Frequency:   equivalent.Frequency,
+				Article:     equivalent.Article,
+				Gender:      genderOfArticle(equivalent.Article),
 			}
+			wordTranslation.Equivalents = append(wordTranslation.Equivalents, equivalentDetail)
 
-			nestedArray := safeGetInterfaceSlice(rowData, 1)
-			for _, entry := range nestedArray {
-				if arrayEntry, isValid := entry.([]interface{}); isValid {
-					var synonymInfo Synonym
-
-					firstSubEntry := safeGetInterfaceSlice(arrayEntry, 0)
-					var synonymStrings []string
-					for _, synonym := range firstSubEntry {
-						if synonymStr, isValid := synonym.(string); isValid {
-							synonymStrings = append(synonymStrings, synonymStr)
-						}
-					}
-					synonymInfo.Synonyms = synonymStrings
-
-					thirdSubEntry := safeGetInterfaceSlice(arrayEntry, 2)
-					categorySubData := safeGetInterfaceSlice(thirdSubEntry, 0)
-					synonymInfo.Category = safeGetString(categorySubData, 0)
-
-					wordSynonym.Synonyms = append(wordSynonym.Synonyms, synonymInfo)
-				}
+			// The frequency of the word is that of its most frequent sense
+			if equivalentDetail.Frequency > wordTranslation.Frequency {
+				wordTranslation.Frequency = equivalentDetail.Frequency
 			}
-			translation.WordSynonyms = append(translation.WordSynonyms, wordSynonym)
 		}
 	}
 }
 
-// parseWordDefinitions parses the definitions for the words in the text from Google Translate
-func parseWordDefinitions(row []interface{}, result *TranslationResult) {
-	for _, row := range row {
-		if rowData, isValid := row.([]interface{}); isValid {
+// parseAlternateTranslations parses the alternate translations for the text from Google Translate.
+// The segments are aligned with the content and translation, which are parsed before them.
+func parseAlternateTranslations(alternates []rawAlternate, result *TranslationResult) {
+	align := aligner{content: result.Content, translation: result.Translation}
+	for _, alternate := range alternates {
+		altTrans := AlternateTranslation{
+			Content: alternate.Content,
+		}
+		for _, alternative := range alternate.Alternatives {
+			altTrans.Translations = append(altTrans.Translations, Translation{
+				Translation: alternative.Translation,
+				IsCommon:    alternative.IsCommon,
+				IsInformal:  alternative.IsInformal,
+			})
+		}
 
-			wordDefinition := WordDefinition{
-				PartsOfSentence: safeGetString(rowData, 0),
+		for _, span := range alternate.Spans {
+			if sourceSpan, isFound := align.sourceSpan(altTrans.Content, alternate.Sentence, span[0], span[1]); isFound {
+				altTrans.SourceSpans = append(altTrans.SourceSpans, sourceSpan)
 			}
-
-			nestedArray := safeGetInterfaceSlice(rowData, 1)
-			for _, entry := range nestedArray {
-				if arrayEntry, isValid := entry.([]interface{}); isValid {
-					wordDefinition.Definitions = append(wordDefinition.Definitions, Definition{
-						Definition: safeGetString(arrayEntry, 0),
-						Example:    safeGetString(arrayEntry, 2),
-					})
-				}
+		}
+		if len(altTrans.Translations) > 0 {
+			if targetSpan, isFound := align.targetSpan(altTrans.Translations[0].Translation); isFound {
+				altTrans.TargetSpans = append(altTrans.TargetSpans, targetSpan)
 			}
-			result.WordDefinitions = append(result.WordDefinitions, wordDefinition)
 		}
+		result.AlternateTranslations = append(result.AlternateTranslations, altTrans)
 	}
 }
 
-// parseWordExamples parses the examples for the words in the text from Google Translate
-func parseWordExamples(row []interface{}, result *TranslationResult) {
-	for _, item := range row {
-		if rowData, isValid := item.([]interface{}); isValid {
-			for _, data := range rowData {
-				if example, isValid := data.([]interface{}); isValid {
-					result.WordExamples = append(result.WordExamples, safeGetString(example, 0))
-				}
-			}
+// parseWordSynonyms parses the synonyms for the words in the text from Google Translate
+func parseWordSynonyms(entries []rawSynonymEntry, translation *TranslationResult) {
+	for _, entry := range entries {
+		wordSynonym := WordSynonym{
+			PartsOfSentence: entry.PartOfSpeechName,
+			Contents:        entry.Content,
+			Frequency:       entry.Frequency,
+		}
+		for _, set := range entry.Sets {
+			wordSynonym.Synonyms = append(wordSynonym.Synonyms, Synonym{
+				Category: set.Category,
+				Synonyms: set.Synonyms,
+			})
 		}
+		translation.WordSynonyms = append(translation.WordSynonyms, wordSynonym)
 	}
 }
 
-// parseRow calls the appropriate parsing function based on the row index
-func parseRow(row []interface{}, idx int, translationResult *TranslationResult) {
-	if row, isValid := row[idx].([]interface{}); isValid {
-		switch idx {
-		case 0:
-			parseOverallTranslation(row, translationResult)
-		case 1:
-			parseDetailedTranslation(row, translationResult)
-		case 5:
-			parseAlternateTranslations(row, translationResult)
-		case 11:
-			parseWordSynonyms(row, translationResult)
-		case 12:
-			parseWordDefinitions(row, translationResult)
-		case 13:
-			parseWordExamples(row, translationResult)
-		default:
-			// Handle other row indexes as needed
+// parseWordDefinitions parses the definitions for the words in the text from Google Translate
+func parseWordDefinitions(entries []rawDefinitionEntry, result *TranslationResult) {
+	for _, entry := range entries {
+		wordDefinition := WordDefinition{
+			PartsOfSentence: entry.PartOfSpeechName,
+		}
+		for _, definition := range entry.Definitions {
+			wordDefinition.Definitions = append(wordDefinition.Definitions, Definition{
+				Definition: definition.Definition,
+				Example:    definition.Example,
+			})
 		}
+		result.WordDefinitions = append(result.WordDefinitions, wordDefinition)
 	}
 }
 
-// extractTranslationData constructs a TranslationResult from the parsed JSON data
-func extractTranslationData(jsonData []interface{}) *TranslationResult {
+// parseRelatedWords parses the related "see also" words for the text from Google Translate
+func parseRelatedWords(groups [][]string, result *TranslationResult) {
+	for _, words := range groups {
+		result.RelatedWords = append(result.RelatedWords, RelatedWordGroup{Words: words})
+	}
+}
+
+// parseSpellingCorrection parses the corrected content suggested by Google Translate
+func parseSpellingCorrection(correction *rawCorrection, result *TranslationResult) {
+	result.CorrectedContentHTML = correction.HTML
+	result.CorrectedContent = correction.Text
+	result.Autocorrected = correction.Autocorrected
+}
+
+// parseLanguageDetection parses the detected source languages, their confidence and the language correction from Google Translate
+func parseLanguageDetection(detection *rawDetection, result *TranslationResult, sections Section) {
+	if sections.has(SectionLanguageDetection) && len(detection.Confidences) > 0 {
+		result.SourceLanguageConfidence = detection.Confidences[0]
+	}
+	if sections.has(SectionSpellingCorrection) && len(detection.Languages) > 0 {
+		result.SuggestedSourceLanguageCode = detection.Languages[0]
+	}
+}
+
+// extractTranslationData constructs a TranslationResult from the decoded response, populating the requested sections
+func extractTranslationData(raw *rawTranslation, sections Section) *TranslationResult {
 	var translationResult TranslationResult
-	for rowIndex := range jsonData {
-		parseRow(jsonData, rowIndex, &translationResult)
+	if sections.has(SectionTranslation|SectionTransliteration) && raw.Sentences != nil {
+		parseOverallTranslation(raw.Sentences, &translationResult, sections)
+	}
+	if sections.has(SectionDictionary) && raw.Dictionary != nil {
+		parseDetailedTranslation(raw.Dictionary, &translationResult)
+	}
+	if sections.has(SectionAlternateTranslations) {
+		parseAlternateTranslations(raw.Alternates, &translationResult)
+	}
+	if sections.has(SectionSpellingCorrection) && raw.Correction != nil {
+		parseSpellingCorrection(raw.Correction, &translationResult)
+	}
+	if raw.Detection != nil {
+		parseLanguageDetection(raw.Detection, &translationResult, sections)
+	}
+	if sections.has(SectionSynonyms) {
+		parseWordSynonyms(raw.Synonyms, &translationResult)
+	}
+	if sections.has(SectionDefinitions) {
+		parseWordDefinitions(raw.Definitions, &translationResult)
+	}
+	if sections.has(SectionExamples) {
+		translationResult.WordExamples = raw.Examples
+	}
+	if sections.has(SectionRelatedWords) {
+		parseRelatedWords(raw.RelatedWords, &translationResult)
 	}
 
-	translationResult.SourceLanguage, _ = language.Parse(safeGetString(jsonData, 2))
+	translationResult.SourceLanguageCode = raw.SourceLanguage
+	translationResult.SourceLanguage, _ = GoogleLanguageCodec.Tag(translationResult.SourceLanguageCode)
+	translationResult.Confidence = raw.Confidence
+
+	// The most likely language is only a suggestion when it differs from the source language
+	if translationResult.SuggestedSourceLanguageCode == translationResult.SourceLanguageCode {
+		translationResult.SuggestedSourceLanguageCode = ""
+	}
+	translationResult.SuggestedSourceLanguage, _ = GoogleLanguageCodec.Tag(translationResult.SuggestedSourceLanguageCode)
 
 	return &translationResult
 }
 
 // parseTranslationJSON takes the raw JSON data from Google Translate API and returns a TranslationResult structure
-func parseTranslationJSON(jsonData []byte) (*TranslationResult, error) {
-	var rawTranslationData []interface{}
+// with the fields of the requested sections, together with the parts of the response that did not match the expected layout
+func parseTranslationJSON(jsonData []byte, sections Section) (*TranslationResult, []DecodeDiagnostic, error) {
+	var rawTranslationData rawTranslation
 	err := json.Unmarshal(jsonData, &rawTranslationData)
 	if err != nil {
-		return nil, errors.Join(errors.New("unable to parse the response from google translate api"), err)
+		return nil, nil, errors.Join(ErrMalformedResponse, err)
+	}
+
+	return extractTranslationData(&rawTranslationData, sections), rawTranslationData.diagnostics, nil
+}
+
+// batchItem is a single translated item of a batch response,
+// decoded from either a plain string or a [translation, language] pair
+type batchItem struct {
+	decoded
+	Translation    string
+	SourceLanguage string
+}
+
+// UnmarshalJSON decodes the item from a string or a positional array
+func (b *batchItem) UnmarshalJSON(data []byte) error {
+	if jsonType(data) == "string" {
+		return json.Unmarshal(data, &b.Translation)
+	}
+	p, err := newPositions(data)
+	if err != nil {
+		return err
 	}
+	b.Translation = p.string(0)
+	b.SourceLanguage = p.string(1)
+	b.diagnostics = p.finish()
+	return nil
+}
 
-	return extractTranslationData(rawTranslationData), nil
+// parseBatchJSON takes the raw JSON data from the batch endpoint and returns its items,
+// together with the parts of the response that did not match the expected layout.
+// Depending on the request, each item is either a plain string or a [translation, language] pair.
+func parseBatchJSON(jsonData []byte) ([]batchItem, []DecodeDiagnostic, error) {
+	switch jsonType(jsonData) {
+	case "string":
+		var item batchItem
+		if err := json.Unmarshal(jsonData, &item); err != nil {
+			return nil, nil, errors.Join(ErrMalformedResponse, err)
+		}
+		return []batchItem{item}, nil, nil
+	case "array":
+	default:
+		return nil, nil, fmt.Errorf("%w: unexpected batch response", ErrMalformedResponse)
+	}
+
+	p, err := newPositions(jsonData)
+	if err != nil {
+		return nil, nil, errors.Join(ErrMalformedResponse, err)
+	}
+
+	// A single item requested with automatic detection is returned as a bare pair
+	if p.len() == 2 && jsonType(p.items[0]) == "string" && jsonType(p.items[1]) == "string" {
+		var pair batchItem
+		if err := json.Unmarshal(jsonData, &pair); err == nil && !strings.HasPrefix(pair.SourceLanguage, "<") {
+			return []batchItem{pair}, pair.diagnostics, nil
+		}
+	}
+
+	items := make([]batchItem, 0, p.len())
+	for i := 0; i < p.len(); i++ {
+		var item batchItem
+		if p.decode(i, &item, "string or array") {
+			items = append(items, item)
+		}
+	}
+	return items, p.finish(), nil
 }
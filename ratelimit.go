@@ -0,0 +1,128 @@
+package gtranslate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token-bucket limiter with separate budgets for requests and characters per second.
+// A single RateLimiter may be shared by several clients to throttle them together.
+type RateLimiter struct {
+	mu         sync.Mutex
+	requests   tokenBucket
+	characters tokenBucket
+	stats      RateLimiterStats
+}
+
+// RateLimiterStats reports how much callers have been throttled by a RateLimiter
+type RateLimiterStats struct {
+	Waiting   int           // The number of calls currently blocked waiting for budget.
+	Waits     int64         // The total number of calls that had to wait.
+	TotalWait time.Duration // The total time calls were asked to wait.
+	LastWait  time.Duration // The wait imposed on the most recent call that had to wait.
+}
+
+// tokenBucket holds the budget of a single resource. A zero rate means unlimited.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing the given number of requests and characters per second.
+// Zero or negative values leave the corresponding budget unlimited. Each budget allows bursts
+// of up to one second worth of tokens.
+func NewRateLimiter(requestsPerSecond, charactersPerSecond float64) *RateLimiter {
+	return &RateLimiter{
+		requests:   newTokenBucket(requestsPerSecond),
+		characters: newTokenBucket(charactersPerSecond),
+	}
+}
+
+// WithRateLimit throttles all calls of the client to the given requests and characters per second
+func WithRateLimit(requestsPerSecond, charactersPerSecond float64) Option {
+	return func(c *Client) {
+		c.rateLimiter = NewRateLimiter(requestsPerSecond, charactersPerSecond)
+	}
+}
+
+// WithRateLimiter throttles all calls of the client with the given, possibly shared, limiter
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request carrying the given number of characters fits in the budget,
+// or until the context is done
+func (l *RateLimiter) Wait(ctx context.Context, characters int) error {
+	l.mu.Lock()
+	now := time.Now()
+	delay := l.requests.reserve(1, now)
+	if d := l.characters.reserve(float64(characters), now); d > delay {
+		delay = d
+	}
+	if delay <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	l.stats.Waiting++
+	l.stats.Waits++
+	l.stats.TotalWait += delay
+	l.stats.LastWait = delay
+	l.mu.Unlock()
+
+	err := sleepContext(ctx, delay)
+
+	l.mu.Lock()
+	l.stats.Waiting--
+	if err != nil {
+		// The request will not be sent, so its reservation is given back
+		l.requests.tokens++
+		l.characters.tokens += float64(characters)
+	}
+	l.mu.Unlock()
+	return err
+}
+
+// Stats returns the current wait statistics of the limiter
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// newTokenBucket creates a full bucket refilled at the given rate
+func newTokenBucket(rate float64) tokenBucket {
+	if rate <= 0 {
+		return tokenBucket{}
+	}
+	return tokenBucket{rate: rate, burst: rate, tokens: rate}
+}
+
+// reserve takes n tokens from the bucket and returns how long to wait before they are available.
+// The bucket may go into debt, so that requests larger than the burst are still served eventually.
+func (b *tokenBucket) reserve(n float64, now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// RateLimiter returns the limiter throttling the client, or nil if the client is not throttled
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.rateLimiter
+}
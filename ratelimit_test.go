@@ -0,0 +1,143 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name  string
+		rate  float64
+		steps []float64 // Tokens taken at each step, one step every 100ms.
+		want  []time.Duration
+	}{
+		{"within the burst", 10, []float64{5, 5}, []time.Duration{0, 0}},
+		{"debt", 10, []float64{10, 5, 1}, []time.Duration{0, 400 * time.Millisecond, 400 * time.Millisecond}},
+		{"larger than the burst", 10, []float64{25}, []time.Duration{1500 * time.Millisecond}},
+		{"refilled", 10, []float64{10, 1, 1}, []time.Duration{0, 0, 0}},
+		{"unlimited", 0, []float64{1000, 1000}, []time.Duration{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(tt.rate)
+			for i, n := range tt.steps {
+				now := start.Add(time.Duration(i) * 100 * time.Millisecond)
+				if got := b.reserve(n, now); got != tt.want[i] {
+					t.Errorf("step %d: got %s, want %s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenBucketBurstCap(t *testing.T) {
+	start := time.Now()
+	b := newTokenBucket(10)
+	b.reserve(10, start)
+	b.reserve(0, start.Add(time.Minute))
+	if b.tokens != b.burst {
+		t.Errorf("got %v tokens after a long idle time, want the burst of %v", b.tokens, b.burst)
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(20, 0)
+	start := time.Now()
+	for i := 0; i < 21; i++ {
+		if err := l.Wait(context.Background(), 1); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("21 calls took %s, want the last one to wait for about 50ms", elapsed)
+	}
+
+	stats := l.Stats()
+	if stats.Waits != 1 || stats.Waiting != 0 {
+		t.Errorf("got %d waits and %d waiting, want 1 and 0", stats.Waits, stats.Waiting)
+	}
+	if stats.LastWait <= 0 || stats.LastWait > 50*time.Millisecond || stats.TotalWait != stats.LastWait {
+		t.Errorf("got last wait %s and total wait %s, want a single wait of at most 50ms", stats.LastWait, stats.TotalWait)
+	}
+}
+
+func TestRateLimiterRefund(t *testing.T) {
+	tests := []struct {
+		name              string
+		requests, chars   float64
+		first, second     int
+		wantWait          time.Duration
+		bucket            func(*RateLimiter) *tokenBucket
+		wantRefundedAbove float64
+	}{
+		{
+			name:     "request budget",
+			requests: 1, first: 1, second: 1,
+			wantWait:          time.Second,
+			bucket:            func(l *RateLimiter) *tokenBucket { return &l.requests },
+			wantRefundedAbove: -0.1,
+		},
+		{
+			name:  "character budget",
+			chars: 100, first: 100, second: 50,
+			wantWait:          500 * time.Millisecond,
+			bucket:            func(l *RateLimiter) *tokenBucket { return &l.characters },
+			wantRefundedAbove: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(tt.requests, tt.chars)
+			if err := l.Wait(context.Background(), tt.first); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if err := l.Wait(ctx, tt.second); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("got error %v, want context.DeadlineExceeded", err)
+			}
+
+			stats := l.Stats()
+			if stats.Waits != 1 || stats.Waiting != 0 {
+				t.Errorf("got %d waits and %d waiting, want 1 and 0", stats.Waits, stats.Waiting)
+			}
+			if stats.LastWait > tt.wantWait || stats.LastWait < tt.wantWait-50*time.Millisecond {
+				t.Errorf("got last wait %s, want about %s", stats.LastWait, tt.wantWait)
+			}
+
+			// The cancelled call gave its reservation back, so the bucket is no longer in debt
+			if tokens := tt.bucket(l).tokens; tokens < tt.wantRefundedAbove {
+				t.Errorf("got %v tokens after the refund, want the debt to be cleared", tokens)
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaiting(t *testing.T) {
+	l := NewRateLimiter(1, 0)
+	l.Wait(context.Background(), 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- l.Wait(ctx, 0) }()
+
+	deadline := time.Now().Add(time.Second)
+	for l.Stats().Waiting != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the call never started waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if waiting := l.Stats().Waiting; waiting != 0 {
+		t.Errorf("got %d waiting after cancellation, want 0", waiting)
+	}
+}
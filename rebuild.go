@@ -0,0 +1,222 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// ErrInvalidSelection is returned when a chosen segment or alternate does not exist in the result
+var ErrInvalidSelection = errors.New("invalid alternate translation selection")
+
+// Define the punctuation that is written without a space before or after it
+const (
+	closingPunctuation = ",.;:!?)]}»…%，。！？、：；）」』"
+	openingPunctuation = "([{«¿¡（「『"
+)
+
+// Rebuild reassembles the translation of a result using the chosen alternates.
+// The choices map the index of a segment in AlternateTranslations to the index of the chosen
+// alternate in its Translations; segments without a choice keep their current translation.
+// When the chosen segments are located in the translation, only their text is replaced and the
+// rest of the translation, including spacing and punctuation, is kept. Otherwise the translation
+// is rebuilt by joining the translations of all segments.
+func Rebuild(result *TranslationResult, choices map[int]int) (string, error) {
+	if err := checkChoices(result, choices); err != nil {
+		return "", err
+	}
+	if segments, isAligned := alignedChoices(result, choices); isAligned {
+		return replaceSegments(result, choices, segments), nil
+	}
+	return joinSegments(result, choices), nil
+}
+
+// RebuildTail applies the chosen alternate of a segment and translates the rest of its sentence again.
+// Unlike the web interface, the tail is NOT conditioned on the chosen segment: the unofficial endpoint
+// cannot be given the chosen prefix, so the tail of the sentence is translated on its own and joined
+// to the chosen alternate. Its wording, agreement and word order may therefore not fit the alternate.
+// The segment must be aligned with the content and translation of the result, see AlternateTranslation;
+// spans that do not fit them, for instance after replacing the translation, fail with ErrInvalidSelection.
+func (c *Client) RebuildTail(ctx context.Context, result *TranslationResult, segment, alternate int, targetLanguage language.Tag) (string, error) {
+	if err := checkChoices(result, map[int]int{segment: alternate}); err != nil {
+		return "", err
+	}
+	seg := result.AlternateTranslations[segment]
+	if len(seg.SourceSpans) == 0 || len(seg.TargetSpans) == 0 {
+		return "", fmt.Errorf("%w: segment %d is not aligned with the content", ErrInvalidSelection, segment)
+	}
+
+	sourceEnd := seg.SourceSpans[len(seg.SourceSpans)-1].End
+	target := seg.TargetSpans[0]
+	if sourceEnd < 0 || sourceEnd > len(result.Content) ||
+		target.Start < 0 || target.Start > target.End || target.End > len(result.Translation) {
+		return "", fmt.Errorf("%w: spans of segment %d do not fit the result", ErrInvalidSelection, segment)
+	}
+	sourceSentenceEnd, targetSentenceEnd := sentenceEnds(result, sourceEnd)
+	if targetSentenceEnd < target.End {
+		targetSentenceEnd = target.End
+	}
+
+	prefix := result.Translation[:target.Start] + seg.Translations[alternate].Translation
+	suffix := result.Translation[targetSentenceEnd:]
+
+	// A tail of only spaces and punctuation is kept as it is
+	tail := result.Content[sourceEnd:sourceSentenceEnd]
+	core := strings.TrimSpace(tail)
+	if strings.IndexFunc(core, isWordCharacter) < 0 {
+		return prefix + tail + suffix, nil
+	}
+
+	translated, err := c.Translate(ctx, core, result.SourceLanguage, targetLanguage)
+	if err != nil {
+		return "", fmt.Errorf("translate sentence tail: %w", err)
+	}
+
+	leading := tail[:strings.Index(tail, core)]
+	trailing := tail[len(leading)+len(core):]
+	if leading == "" && needsSpace(prefix, translated.Translation) {
+		leading = " "
+	}
+	return prefix + leading + translated.Translation + trailing + suffix, nil
+}
+
+// checkChoices verifies that every chosen segment and alternate exists in the result
+func checkChoices(result *TranslationResult, choices map[int]int) error {
+	if result == nil {
+		return fmt.Errorf("%w: no result", ErrInvalidSelection)
+	}
+	for segment, alternate := range choices {
+		if segment < 0 || segment >= len(result.AlternateTranslations) {
+			return fmt.Errorf("%w: no segment %d", ErrInvalidSelection, segment)
+		}
+		if alternate < 0 || alternate >= len(result.AlternateTranslations[segment].Translations) {
+			return fmt.Errorf("%w: no alternate %d for segment %d", ErrInvalidSelection, alternate, segment)
+		}
+	}
+	return nil
+}
+
+// alignedChoices returns the chosen segments ordered by their position in the translation.
+// It reports false when a chosen segment is not located in the translation or segments overlap.
+func alignedChoices(result *TranslationResult, choices map[int]int) ([]int, bool) {
+	segments := make([]int, 0, len(choices))
+	for segment := range choices {
+		spans := result.AlternateTranslations[segment].TargetSpans
+		if len(spans) == 0 || spans[0].Start < 0 || spans[0].End > len(result.Translation) {
+			return nil, false
+		}
+		segments = append(segments, segment)
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return result.AlternateTranslations[segments[i]].TargetSpans[0].Start <
+			result.AlternateTranslations[segments[j]].TargetSpans[0].Start
+	})
+
+	end := 0
+	for _, segment := range segments {
+		span := result.AlternateTranslations[segment].TargetSpans[0]
+		if span.Start < end {
+			return nil, false
+		}
+		end = span.End
+	}
+	return segments, true
+}
+
+// replaceSegments replaces the translation of the given ordered segments by their chosen alternates
+func replaceSegments(result *TranslationResult, choices map[int]int, segments []int) string {
+	var rebuilt strings.Builder
+	end := 0
+	for _, segment := range segments {
+		span := result.AlternateTranslations[segment].TargetSpans[0]
+		replacement := result.AlternateTranslations[segment].Translations[choices[segment]].Translation
+
+		gap := result.Translation[end:span.Start]
+		if startsWithAny(replacement, closingPunctuation) {
+			gap = strings.TrimRightFunc(gap, unicode.IsSpace)
+		}
+		rebuilt.WriteString(gap)
+		rebuilt.WriteString(replacement)
+		end = span.End
+	}
+	rebuilt.WriteString(result.Translation[end:])
+	return rebuilt.String()
+}
+
+// joinSegments joins the chosen, or current, translation of every segment
+func joinSegments(result *TranslationResult, choices map[int]int) string {
+	var rebuilt string
+	for i, segment := range result.AlternateTranslations {
+		if len(segment.Translations) == 0 {
+			continue
+		}
+		translation := segment.Translations[0].Translation
+		if alternate, isChosen := choices[i]; isChosen {
+			translation = segment.Translations[alternate].Translation
+		}
+		if needsSpace(rebuilt, translation) {
+			rebuilt += " "
+		}
+		rebuilt += translation
+	}
+	return rebuilt
+}
+
+// sentenceEnds returns the byte offsets of the end of the sentence containing the given
+// content offset, in the content and in the translation. The whole text is used when the
+// sentences do not add up to the content.
+func sentenceEnds(result *TranslationResult, contentOffset int) (int, int) {
+	contentEnd, translationEnd := 0, 0
+	for _, sentence := range result.TranslatedSentences {
+		contentEnd += len(sentence.Content)
+		translationEnd += len(sentence.Translation)
+		if contentOffset <= contentEnd {
+			break
+		}
+	}
+	if contentEnd > len(result.Content) || translationEnd > len(result.Translation) || contentOffset > contentEnd {
+		return len(result.Content), len(result.Translation)
+	}
+	return contentEnd, translationEnd
+}
+
+// needsSpace reports whether a space is needed between two pieces of text.
+// No space is added next to existing spaces, around punctuation, or between scripts written without spaces.
+func needsSpace(left, right string) bool {
+	if left == "" || right == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(left)
+	first, _ := utf8.DecodeRuneInString(right)
+	switch {
+	case unicode.IsSpace(last) || unicode.IsSpace(first):
+		return false
+	case strings.ContainsRune(closingPunctuation, first) || strings.ContainsRune(openingPunctuation, last):
+		return false
+	case isWrittenWithoutSpaces(last) || isWrittenWithoutSpaces(first):
+		return false
+	}
+	return true
+}
+
+// isWordCharacter reports whether the rune is a letter or a number
+func isWordCharacter(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// isWrittenWithoutSpaces reports whether the rune belongs to a script that does not separate words with spaces
+func isWrittenWithoutSpaces(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
+
+// startsWithAny reports whether the text starts with one of the given characters
+func startsWithAny(text, chars string) bool {
+	first, size := utf8.DecodeRuneInString(text)
+	return size > 0 && strings.ContainsRune(chars, first)
+}
@@ -0,0 +1,321 @@
+package gtranslate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// segment builds an alternate translation located at the given byte offsets of the translation
+func segment(content string, start, end int, translations ...string) AlternateTranslation {
+	alternate := AlternateTranslation{Content: content, TargetSpans: []TextSpan{{Start: start, End: end}}}
+	for _, translation := range translations {
+		alternate.Translations = append(alternate.Translations, Translation{Translation: translation})
+	}
+	return alternate
+}
+
+func TestRebuild(t *testing.T) {
+	houseIsRed := func() *TranslationResult {
+		return &TranslationResult{
+			Translation: "Das Haus ist rot.",
+			AlternateTranslations: []AlternateTranslation{
+				segment("The house", 0, 8, "Das Haus", "Das Gebäude"),
+				segment("is", 9, 12, "ist", "wirkt"),
+				segment("red", 13, 16, "rot", "rötlich"),
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		result  *TranslationResult
+		choices map[int]int
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no choices",
+			result:  houseIsRed(),
+			choices: map[int]int{},
+			want:    "Das Haus ist rot.",
+		},
+		{
+			name:    "single choice keeps the punctuation",
+			result:  houseIsRed(),
+			choices: map[int]int{2: 1},
+			want:    "Das Haus ist rötlich.",
+		},
+		{
+			name:    "several choices",
+			result:  houseIsRed(),
+			choices: map[int]int{2: 1, 0: 1, 1: 1},
+			want:    "Das Gebäude wirkt rötlich.",
+		},
+		{
+			name: "closing punctuation drops the space before it",
+			result: &TranslationResult{
+				Translation: "Hallo Welt",
+				AlternateTranslations: []AlternateTranslation{
+					segment("Hello", 0, 5, "Hallo"),
+					segment("world", 6, 10, "Welt", ", Welt"),
+				},
+			},
+			choices: map[int]int{1: 1},
+			want:    "Hallo, Welt",
+		},
+		{
+			name: "overlapping spans fall back to joining the segments",
+			result: &TranslationResult{
+				Translation: "Das Haus ist rot.",
+				AlternateTranslations: []AlternateTranslation{
+					segment("The house", 0, 8, "Das Haus", "Das Gebäude"),
+					segment("house is", 4, 12, "Haus ist", "Gebäude ist"),
+				},
+			},
+			choices: map[int]int{0: 1, 1: 0},
+			want:    "Das Gebäude Haus ist",
+		},
+		{
+			name: "unaligned segments fall back to joining the segments",
+			result: &TranslationResult{
+				Translation: "Das Haus ist rot.",
+				AlternateTranslations: []AlternateTranslation{
+					{Content: "The house", Translations: []Translation{{Translation: "Das Haus"}, {Translation: "Das Gebäude"}}},
+					{Content: "is red", Translations: []Translation{{Translation: "ist rot"}}},
+					{Content: ".", Translations: []Translation{{Translation: "."}}},
+				},
+			},
+			choices: map[int]int{0: 1},
+			want:    "Das Gebäude ist rot.",
+		},
+		{
+			name: "stale spans fall back to joining the segments",
+			result: &TranslationResult{
+				Translation: "Das",
+				AlternateTranslations: []AlternateTranslation{
+					segment("The house", 0, 8, "Das Haus", "Das Gebäude"),
+				},
+			},
+			choices: map[int]int{0: 1},
+			want:    "Das Gebäude",
+		},
+		{
+			name:    "nil result",
+			choices: map[int]int{0: 0},
+			wantErr: true,
+		},
+		{
+			name:    "unknown segment",
+			result:  houseIsRed(),
+			choices: map[int]int{3: 0},
+			wantErr: true,
+		},
+		{
+			name:    "negative segment",
+			result:  houseIsRed(),
+			choices: map[int]int{-1: 0},
+			wantErr: true,
+		},
+		{
+			name:    "out-of-range alternate",
+			result:  houseIsRed(),
+			choices: map[int]int{0: 2},
+			wantErr: true,
+		},
+		{
+			name:    "negative alternate",
+			result:  houseIsRed(),
+			choices: map[int]int{0: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Rebuild(tt.result, tt.choices)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSelection) {
+					t.Errorf("got error %v, want ErrInvalidSelection", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []string
+		want     string
+	}{
+		{"words", []string{"Hello", "world"}, "Hello world"},
+		{"closing punctuation", []string{"Hello", ",", "world", "!"}, "Hello, world!"},
+		{"opening punctuation", []string{"say", "(", "hi", ")"}, "say (hi)"},
+		{"existing spaces", []string{"Hello ", "world"}, "Hello world"},
+		{"script without spaces", []string{"你好", "世界", "。"}, "你好世界。"},
+		{"mixed scripts", []string{"東京", "is", "big"}, "東京is big"},
+		{"empty segment", []string{"Hello", "", "world"}, "Hello world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &TranslationResult{}
+			for _, s := range tt.segments {
+				result.AlternateTranslations = append(result.AlternateTranslations, AlternateTranslation{
+					Translations: []Translation{{Translation: s}},
+				})
+			}
+			if got := joinSegments(result, nil); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSentenceEnds(t *testing.T) {
+	twoSentences := &TranslationResult{
+		Content:     "One. Two.",
+		Translation: "Eins. Zwei.",
+		TranslatedSentences: []Sentence{
+			{Content: "One. ", Translation: "Eins. "},
+			{Content: "Two.", Translation: "Zwei."},
+		},
+	}
+	tests := []struct {
+		name            string
+		result          *TranslationResult
+		offset          int
+		wantContent     int
+		wantTranslation int
+	}{
+		{"inside the first sentence", twoSentences, 2, 5, 6},
+		{"at the end of the first sentence", twoSentences, 5, 5, 6},
+		{"inside the second sentence", twoSentences, 7, 9, 11},
+		{"past the content", twoSentences, 20, 9, 11},
+		{
+			name: "sentences longer than the content",
+			result: &TranslationResult{
+				Content:             "One.",
+				Translation:         "Eins.",
+				TranslatedSentences: []Sentence{{Content: "One. Two.", Translation: "Eins. Zwei."}},
+			},
+			offset: 2, wantContent: 4, wantTranslation: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, translation := sentenceEnds(tt.result, tt.offset)
+			if content != tt.wantContent || translation != tt.wantTranslation {
+				t.Errorf("got %d, %d, want %d, %d", content, translation, tt.wantContent, tt.wantTranslation)
+			}
+		})
+	}
+}
+
+// redHouse is the translation of a sentence whose first segment has an alternate
+func redHouse() *TranslationResult {
+	return &TranslationResult{
+		Content:     "The red house is big.",
+		Translation: "Das rote Haus ist groß.",
+		TranslatedSentences: []Sentence{
+			{Content: "The red house is big.", Translation: "Das rote Haus ist groß."},
+		},
+		AlternateTranslations: []AlternateTranslation{{
+			Content:      "The red house",
+			Translations: []Translation{{Translation: "Das rote Haus"}, {Translation: "Das rote Gebäude"}},
+			SourceSpans:  []TextSpan{{Start: 0, End: 13}},
+			TargetSpans:  []TextSpan{{Start: 0, End: 13}},
+		}},
+	}
+}
+
+func TestRebuildTail(t *testing.T) {
+	// The stand-in server translates by upper-casing the query
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		q := r.Form.Get("q")
+		queries = append(queries, q)
+		json.NewEncoder(w).Encode([]interface{}{[]interface{}{[]interface{}{strings.ToUpper(q), q, nil, nil, 10}}, nil, "en"})
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithTranslateURL(srv.URL), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false))
+	got, err := client.RebuildTail(context.Background(), redHouse(), 0, 1, language.German)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Das rote Gebäude IS BIG."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(queries) != 1 || queries[0] != "is big." {
+		t.Errorf("got queries %q, want the tail of the sentence only", queries)
+	}
+}
+
+func TestRebuildTailInvalidSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    func() *TranslationResult
+		segment   int
+		alternate int
+	}{
+		{"nil result", func() *TranslationResult { return nil }, 0, 0},
+		{"unknown segment", redHouse, 1, 0},
+		{"out-of-range alternate", redHouse, 0, 2},
+		{
+			name: "unaligned segment",
+			result: func() *TranslationResult {
+				r := redHouse()
+				r.AlternateTranslations[0].TargetSpans = nil
+				return r
+			},
+		},
+		{
+			name: "stale target span",
+			result: func() *TranslationResult {
+				r := redHouse()
+				r.Translation = "Das"
+				return r
+			},
+		},
+		{
+			name: "stale source span",
+			result: func() *TranslationResult {
+				r := redHouse()
+				r.Content = "The"
+				return r
+			},
+		},
+		{
+			name: "inverted target span",
+			result: func() *TranslationResult {
+				r := redHouse()
+				r.AlternateTranslations[0].TargetSpans[0] = TextSpan{Start: 10, End: 4}
+				return r
+			},
+		},
+	}
+	// Invalid selections fail before any request is sent
+	client := NewClient(WithTranslateURL("http://127.0.0.1:1"), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.RebuildTail(context.Background(), tt.result(), tt.segment, tt.alternate, language.German)
+			if !errors.Is(err, ErrInvalidSelection) {
+				t.Errorf("got error %v, want ErrInvalidSelection", err)
+			}
+		})
+	}
+}
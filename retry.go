@@ -0,0 +1,94 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy controls how requests failing with a transient error are retried.
+// Rate limiting (429), server errors (5xx) and network timeouts are considered transient.
+type RetryPolicy struct {
+	MaxAttempts int                // The total number of attempts, including the first one. Values below 2 disable retries.
+	BaseDelay   time.Duration      // The delay before the first retry, doubled on each following retry.
+	MaxDelay    time.Duration      // The upper bound of the computed backoff delay, zero for no bound.
+	Jitter      float64            // The fraction, between 0 and 1, of each delay that is randomized.
+	OnRetry     func(RetryAttempt) // An optional hook called before waiting for each retry.
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried
+type RetryAttempt struct {
+	Attempt int           // The number of the failed attempt, starting at 1.
+	Delay   time.Duration // The delay before the next attempt.
+	Err     error         // The error of the failed attempt.
+}
+
+// DefaultRetryPolicy returns a policy suited to batch jobs: four attempts with
+// exponential backoff starting at half a second, capped at 30 seconds, with 20% jitter
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests. By default requests are not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// delay computes the wait before retrying the given failed attempt.
+// A Retry-After delay sent by the server takes precedence when it is longer than the backoff.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	backoff := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || backoff < p.MaxDelay); i++ {
+		backoff *= 2
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		backoff -= time.Duration(rand.Float64() * jitter * float64(backoff))
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > backoff {
+		return apiErr.RetryAfter
+	}
+	return backoff
+}
+
+// isRetryable reports whether a request failing with err may succeed when sent again
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleepContext waits for the given delay or until the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
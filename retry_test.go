@@ -0,0 +1,155 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	capped := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{"first retry", capped, 1, nil, 100 * time.Millisecond},
+		{"doubled", capped, 3, nil, 400 * time.Millisecond},
+		{"capped", capped, 5, nil, time.Second},
+		{"capped after many attempts", capped, 100, nil, time.Second},
+		{"uncapped", RetryPolicy{BaseDelay: 100 * time.Millisecond}, 5, nil, 1600 * time.Millisecond},
+		{
+			name: "longer Retry-After wins", policy: capped, attempt: 1,
+			err:  &APIError{StatusCode: 429, RetryAfter: 5 * time.Second, Err: ErrRateLimited},
+			want: 5 * time.Second,
+		},
+		{
+			name: "Retry-After beyond the cap", policy: capped, attempt: 5,
+			err:  fmt.Errorf("translate: %w", &APIError{StatusCode: 503, RetryAfter: 10 * time.Second, Err: ErrServer}),
+			want: 10 * time.Second,
+		},
+		{
+			name: "shorter Retry-After", policy: capped, attempt: 2,
+			err:  &APIError{StatusCode: 429, RetryAfter: 10 * time.Millisecond, Err: ErrRateLimited},
+			want: 200 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.delay(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		jitter   float64
+		min, max time.Duration
+	}{
+		{"partial jitter", 0.2, 80 * time.Millisecond, 100 * time.Millisecond},
+		{"full jitter", 1, 0, 100 * time.Millisecond},
+		{"jitter above one", 3, 0, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: tt.jitter}
+			for i := 0; i < 1000; i++ {
+				if got := policy.delay(1, nil); got < tt.min || got > tt.max {
+					t.Fatalf("got %s, want a delay between %s and %s", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryRewindsBody(t *testing.T) {
+	// The stand-in server fails twice, checking that every attempt carries the query in its body
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Method != "POST" {
+			t.Errorf("attempt %d: got method %s, want POST", attempts, r.Method)
+		}
+		r.ParseForm()
+		if q := r.PostForm.Get("q"); q != "Hello" {
+			t.Errorf("attempt %d: got query %q in the body, want %q", attempts, q, "Hello")
+		}
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, capturedHello)
+		}
+	}))
+	defer srv.Close()
+
+	var retries []RetryAttempt
+	client := NewClient(
+		WithTranslateURL(srv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithLanguageValidation(false),
+		WithMaxURLLength(0),
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			OnRetry:     func(attempt RetryAttempt) { retries = append(retries, attempt) },
+		}),
+	)
+	result, err := client.Translate(context.Background(), "Hello", language.English, language.German)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Translation != "Hallo" {
+		t.Errorf("got translation %q, want %q", result.Translation, "Hallo")
+	}
+
+	if len(retries) != 2 {
+		t.Fatalf("got %d retries, want 2", len(retries))
+	}
+	var gotAttempts []int
+	for _, retry := range retries {
+		gotAttempts = append(gotAttempts, retry.Attempt)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(gotAttempts, want) {
+		t.Errorf("got attempts %v, want %v", gotAttempts, want)
+	}
+	if !errors.Is(retries[0].Err, ErrServer) || !errors.Is(retries[1].Err, ErrRateLimited) {
+		t.Errorf("got errors %v and %v, want ErrServer and ErrRateLimited", retries[0].Err, retries[1].Err)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := NewClient(
+		WithTranslateURL(srv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithLanguageValidation(false),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	)
+	if _, err := client.Translate(context.Background(), "Hello", language.English, language.German); !errors.Is(err, ErrServer) {
+		t.Errorf("got error %v, want ErrServer", err)
+	}
+	if attempts != 3 {
+		t.Errorf("got %d attempts, want 3", attempts)
+	}
+}
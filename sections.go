@@ -0,0 +1,70 @@
+package gtranslate
+
+// Section selects a part of the response of the single translation endpoint.
+// Sections are combined with the | operator and map to the dt parameters of the request.
+type Section uint
+
+// Define the sections that can be requested
+const (
+	SectionTranslation           Section = 1 << iota // The translated sentences (dt=t)
+	SectionTransliteration                           // The romanization of the content and translation (dt=rm)
+	SectionDictionary                                // The dictionary entries of single words (dt=bd)
+	SectionDefinitions                               // The definitions of single words (dt=md)
+	SectionSynonyms                                  // The synonyms of single words (dt=ss)
+	SectionExamples                                  // The usage examples of single words (dt=ex)
+	SectionRelatedWords                              // The related words of single words (dt=rw)
+	SectionSpellingCorrection                        // The spelling and language corrections of the content (dt=qca)
+	SectionAlternateTranslations                     // The alternate translations of each segment (dt=at)
+	SectionLanguageDetection                         // The detected source languages and their confidence (dt=ld)
+
+	// SectionAll requests every section, which is the default
+	SectionAll = SectionTranslation | SectionTransliteration | SectionDictionary | SectionDefinitions |
+		SectionSynonyms | SectionExamples | SectionRelatedWords | SectionSpellingCorrection |
+		SectionAlternateTranslations | SectionLanguageDetection
+)
+
+// sectionParameters lists the dt parameter of each section, in the order they are sent
+var sectionParameters = []struct {
+	section Section
+	dt      string
+}{
+	{SectionAlternateTranslations, "at"},
+	{SectionDictionary, "bd"},
+	{SectionExamples, "ex"},
+	{SectionLanguageDetection, "ld"},
+	{SectionDefinitions, "md"},
+	{SectionSpellingCorrection, "qca"},
+	{SectionRelatedWords, "rw"},
+	{SectionTransliteration, "rm"},
+	{SectionSynonyms, "ss"},
+	{SectionTranslation, "t"},
+}
+
+// has reports whether any of the given sections is selected
+func (s Section) has(section Section) bool {
+	return s&section != 0
+}
+
+// dtParameters returns the dt parameter values of the selected sections
+func (s Section) dtParameters() []string {
+	var parameters []string
+	for _, p := range sectionParameters {
+		if s.has(p.section) {
+			parameters = append(parameters, p.dt)
+		}
+	}
+	return parameters
+}
+
+// WithSections sets the sections requested from the single translation endpoint.
+// Only the fields of TranslationResult belonging to these sections are populated;
+// the source language and overall confidence are always set. Zero requests every section.
+// Combine it with Client.With to select sections for a single call.
+func WithSections(sections Section) Option {
+	return func(c *Client) {
+		if sections == 0 {
+			sections = SectionAll
+		}
+		c.sections = sections
+	}
+}
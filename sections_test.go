@@ -0,0 +1,72 @@
+package gtranslate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestWithSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections Section
+		wantDT   []string
+	}{
+		{"zero requests every section", 0, []string{"at", "bd", "ex", "ld", "md", "qca", "rw", "rm", "ss", "t"}},
+		{"every section", SectionAll, []string{"at", "bd", "ex", "ld", "md", "qca", "rw", "rm", "ss", "t"}},
+		{"translation only", SectionTranslation, []string{"t"}},
+		{"combined sections", SectionTranslation | SectionDictionary | SectionTransliteration, []string{"bd", "rm", "t"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dt []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				dt = r.URL.Query()["dt"]
+				fmt.Fprint(w, capturedHello)
+			}))
+			defer srv.Close()
+
+			client := NewClient(
+				WithTranslateURL(srv.URL),
+				WithTKKProvider(StaticTKK(googleTranslateTKK)),
+				WithLanguageValidation(false),
+				WithSections(tt.sections),
+			)
+			if _, err := client.Translate(context.Background(), "Hello", language.English, language.German); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(dt, tt.wantDT) {
+				t.Errorf("got dt parameters %v, want %v", dt, tt.wantDT)
+			}
+		})
+	}
+}
+
+func TestWithSectionsResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, capturedHello)
+	}))
+	defer srv.Close()
+
+	client := NewClient(
+		WithTranslateURL(srv.URL),
+		WithTKKProvider(StaticTKK(googleTranslateTKK)),
+		WithLanguageValidation(false),
+		WithSections(SectionTranslation),
+	)
+	result, err := client.Translate(context.Background(), "Hello", language.English, language.German)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Translation != "Hallo" {
+		t.Errorf("got translation %q, want %q", result.Translation, "Hallo")
+	}
+	if len(result.AlternateTranslations) != 0 {
+		t.Errorf("got %d alternate translations from an unrequested section", len(result.AlternateTranslations))
+	}
+}
@@ -0,0 +1,51 @@
+package gtranslate
+
+import (
+	"context"
+	"golang.org/x/text/language"
+)
+
+// GoogleTETranslator serves every call through the batch endpoint used by TranslateBatch (client=te).
+// It is useful as a fallback when the single translation endpoint (client=gtx) is blocked.
+type GoogleTETranslator struct {
+	client *Client
+}
+
+// Make sure the te backend implements the interface
+var _ Translator = (*GoogleTETranslator)(nil)
+
+// NewGoogleTETranslator creates a backend using the batch endpoint, configured with the options of NewClient
+func NewGoogleTETranslator(opts ...Option) *GoogleTETranslator {
+	return &GoogleTETranslator{client: NewClient(opts...)}
+}
+
+// Name returns the identifier of the Google backend using the te client
+func (t *GoogleTETranslator) Name() string {
+	return "google-te"
+}
+
+// Translate translates a single piece of content as a batch of one item
+func (t *GoogleTETranslator) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	return translateAsBatch(ctx, t, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch translates several pieces of content in a single request
+func (t *GoogleTETranslator) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	return t.client.TranslateBatch(ctx, contents, sourceLanguage, targetLanguage)
+}
+
+// Detect detects the language of the content from the source language reported by the batch endpoint,
+// which does not include a confidence. The content is always sent with automatic detection,
+// regardless of the client's default source language.
+func (t *GoogleTETranslator) Detect(ctx context.Context, content string) (*Detection, error) {
+	results, err := t.client.translateBatch(ctx, []string{content}, language.Und, "auto", "en")
+	if err != nil {
+		return nil, err
+	}
+	return &Detection{Language: results[0].SourceLanguage}, nil
+}
+
+// SupportedLanguages lists the languages supported by Google Translate, named in the display language
+func (t *GoogleTETranslator) SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error) {
+	return t.client.SupportedLanguages(ctx, displayLanguage)
+}
@@ -0,0 +1,190 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Define how long TKK values are cached
+const (
+	defaultTKKExpiry        = time.Hour   // A scraped value.
+	defaultTKKRetryInterval = time.Minute // The fallback value used after a failed scrape, before scraping again.
+)
+
+// ErrInvalidTKK is returned when a TKK value is not of the form <int>.<int>
+var ErrInvalidTKK = errors.New("invalid tkk value")
+
+// TKKProvider supplies the TKK value used to compute the tk token of each request
+type TKKProvider interface {
+	TKK(ctx context.Context) (string, error)
+}
+
+// StaticTKK is a TKKProvider that always returns the same value, useful in tests
+type StaticTKK string
+
+// TKK returns the static value
+func (s StaticTKK) TKK(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// WithTKKProvider sets the provider of the TKK value.
+// By default the value is scraped from the translate web page on the host of the translate URL.
+func WithTKKProvider(provider TKKProvider) Option {
+	return func(c *Client) {
+		c.tkkProvider = provider
+	}
+}
+
+// tkkPatterns match the TKK value in the different forms it has been embedded in the translate web page
+var (
+	tkkPattern     = regexp.MustCompile(`(?i)tkk['"]?\s*[:=]\s*['"](\d+\.-?\d+)['"]`)
+	tkkEvalPattern = regexp.MustCompile(`TKK=eval\('\(\(function\(\)\{var a\\x3d(-?\d+);var b\\x3d(-?\d+);return (\d+)\+`)
+)
+
+// WebTKKProvider scrapes the TKK value from the translate web page and caches it until it expires.
+// When the page cannot be scraped, the last known value is used and scraping is retried after a minute.
+type WebTKKProvider struct {
+	httpClient    *http.Client
+	pageURL       string
+	userAgent     string
+	expiry        time.Duration
+	retryInterval time.Duration
+
+	mu       sync.Mutex
+	value    string
+	expires  time.Time
+	scraping chan struct{} // Closed when the scrape in progress, if any, is done.
+}
+
+// NewWebTKKProvider creates a provider scraping the page at pageURL with the given HTTP client,
+// caching each value for the given expiry
+func NewWebTKKProvider(httpClient *http.Client, pageURL string, expiry time.Duration) *WebTKKProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &WebTKKProvider{
+		httpClient:    httpClient,
+		pageURL:       pageURL,
+		userAgent:     userAgent,
+		expiry:        expiry,
+		retryInterval: defaultTKKRetryInterval,
+	}
+}
+
+// TKK returns the cached TKK value, scraping a fresh one when it has expired.
+// The lock is not held while scraping; concurrent callers wait for the scrape in progress instead.
+func (p *WebTKKProvider) TKK(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	for p.value == "" || !time.Now().Before(p.expires) {
+		if p.scraping == nil {
+			done := make(chan struct{})
+			p.scraping = done
+			p.mu.Unlock()
+			return p.refresh(ctx, done)
+		}
+
+		scraping := p.scraping
+		p.mu.Unlock()
+		select {
+		case <-scraping:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		p.mu.Lock()
+	}
+	value := p.value
+	p.mu.Unlock()
+	return value, nil
+}
+
+// refresh scrapes a fresh value and caches it, then closes done to wake up the waiting callers.
+// After a failed scrape, the previous or last known value is cached for the retry interval only.
+func (p *WebTKKProvider) refresh(ctx context.Context, done chan struct{}) (string, error) {
+	value, err := p.scrape(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.scraping = nil
+	close(done)
+
+	expiry := p.expiry
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		value = p.value
+		if value == "" {
+			value = googleTranslateTKK
+		}
+		if p.retryInterval < expiry {
+			expiry = p.retryInterval
+		}
+	}
+
+	p.value = value
+	p.expires = time.Now().Add(expiry)
+	return value, nil
+}
+
+// Invalidate discards the cached value, so that the next call scrapes a fresh one
+func (p *WebTKKProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expires = time.Time{}
+}
+
+// scrape downloads the translate web page and extracts the TKK value from it
+func (p *WebTKKProvider) scrape(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newAPIError(resp, nil)
+	}
+
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read body: %w", err)
+	}
+
+	return extractTKK(string(page))
+}
+
+// extractTKK finds the TKK value in the source of the translate web page
+func extractTKK(page string) (string, error) {
+	if m := tkkPattern.FindStringSubmatch(page); m != nil {
+		return m[1], nil
+	}
+	if m := tkkEvalPattern.FindStringSubmatch(page); m != nil {
+		a, _ := strconv.Atoi(m[1])
+		b, _ := strconv.Atoi(m[2])
+		return m[3] + "." + strconv.Itoa(a+b), nil
+	}
+	return "", fmt.Errorf("%w: no tkk value in page", ErrMalformedResponse)
+}
+
+// webPageURL returns the address of the web page served on the host of an API URL
+func webPageURL(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return apiURL
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String()
+}
@@ -0,0 +1,138 @@
+package gtranslate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtractTKK(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		want    string
+		wantErr bool
+	}{
+		{"assignment", `window.TKK='448487.932609646';`, "448487.932609646", false},
+		{"object property", `{"tkk":"448487.932609646","hl":"en"}`, "448487.932609646", false},
+		{"unquoted key", `c._TKK: "1.2"`, "1.2", false},
+		{"spaces around the separator", `tkk = '1.2'`, "1.2", false},
+		{"negative second part", `TKK='406398.-1226296'`, "406398.-1226296", false},
+		{"eval", `TKK=eval('((function(){var a\x3d-561111;var b\x3d1234567;return 406398+'.'+(a+b)})())');`, "406398.673456", false},
+		{"eval with negative sum", `TKK=eval('((function(){var a\x3d-3;var b\x3d1;return 5+'.'+(a+b)})())');`, "5.-2", false},
+		{"assignment before eval", `TKK='1.2';TKK=eval('((function(){var a\x3d3;var b\x3d4;return 5+'`, "1.2", false},
+		{"no value", `<html><body>captcha</body></html>`, "", true},
+		{"not a number", `TKK='abc.def'`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractTKK(tt.page)
+			if tt.wantErr {
+				if !errors.Is(err, ErrMalformedResponse) {
+					t.Errorf("got %q, %v, want ErrMalformedResponse", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebTKKProviderFailedScrape(t *testing.T) {
+	var scrapes int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&scrapes, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `TKK='1.2'`)
+	}))
+	defer srv.Close()
+
+	p := NewWebTKKProvider(nil, srv.URL, time.Hour)
+	p.retryInterval = 20 * time.Millisecond
+
+	for i := 0; i < 2; i++ {
+		value, err := p.TKK(context.Background())
+		if err != nil || value != googleTranslateTKK {
+			t.Fatalf("call %d: got %q, %v, want the fallback value", i, value, err)
+		}
+	}
+	if n := atomic.LoadInt32(&scrapes); n != 1 {
+		t.Errorf("got %d scrapes right after a failure, want 1", n)
+	}
+
+	// The fallback is only kept for the retry interval
+	time.Sleep(30 * time.Millisecond)
+	if value, err := p.TKK(context.Background()); err != nil || value != "1.2" {
+		t.Errorf("got %q, %v after the retry interval, want the scraped value", value, err)
+	}
+}
+
+func TestWebTKKProviderConcurrentScrape(t *testing.T) {
+	var scrapes int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&scrapes, 1)
+		<-release
+		fmt.Fprint(w, `TKK='1.2'`)
+	}))
+	defer srv.Close()
+
+	p := NewWebTKKProvider(nil, srv.URL, time.Hour)
+	var wg sync.WaitGroup
+	values := make(chan string, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _ := p.TKK(context.Background())
+			values <- value
+		}()
+	}
+	for atomic.LoadInt32(&scrapes) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The lock is free while the page is downloaded
+	invalidated := make(chan struct{})
+	go func() {
+		p.Invalidate()
+		close(invalidated)
+	}()
+	select {
+	case <-invalidated:
+	case <-time.After(time.Second):
+		t.Fatal("Invalidate blocked while the page was scraped")
+	}
+
+	// Waiting callers give up with their context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.TKK(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(values)
+	for value := range values {
+		if value != "1.2" {
+			t.Errorf("got %q, want the scraped value", value)
+		}
+	}
+	if n := atomic.LoadInt32(&scrapes); n != 1 {
+		t.Errorf("got %d scrapes for concurrent callers, want 1", n)
+	}
+}
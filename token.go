@@ -1,10 +1,13 @@
 package gtranslate
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf16"
 )
 
+// googleTranslateTKK is the last known TKK value, used when the current one cannot be retrieved
 const googleTranslateTKK = "448487.932609646"
 
 func shiftLeftOrRightThenSumOrXor(num int32, optString string) int32 {
@@ -46,15 +49,18 @@ func shiftLeftOrRightThenSumOrXor(num int32, optString string) int32 {
 }
 
 func transformQuery(query string) []int32 {
+	// The original algorithm iterates over JavaScript strings, so the query is converted to UTF-16 code units
+	codeUnits := utf16.Encode([]rune(query))
+
 	// Initialize a slice to store the UTF-8 encoded bytes
 	var bytesArray []int32
 	// Initialize an index variable
 	idx := 0
 
-	// Iterate over each character in the input string
-	for i := 0; i < len(query); i++ {
-		// Get the Unicode code point of the current character
-		charCode := int32(rune(query[i]))
+	// Iterate over each UTF-16 code unit in the input string
+	for i := 0; i < len(codeUnits); i++ {
+		// Get the code unit of the current character
+		charCode := int32(codeUnits[i])
 
 		// If charCode is less than 128, it's a 1-byte UTF-8 character
 		// Simply append it to the bytesArray
@@ -71,8 +77,8 @@ func transformQuery(query string) []int32 {
 				// If charCode is in the surrogate pair range, it's a 4-byte UTF-8 character
 				// The first byte is created by shifting charCode right by 18 bits, then bitwise OR with 240 (11110000 in binary)
 				// The second byte is created by shifting charCode right by 12 bits, bitwise AND with 63 (00111111 in binary), then bitwise OR with 128 (10000000 in binary)
-				if (charCode&64512) == 55296 && i+1 < len(query) && (int(rune(query[i+1]))&64512) == 56320 {
-					charCode = 65536 + ((charCode & 1023) << 10) + (int32(rune(query[i+1])) & 1023)
+				if (charCode&64512) == 55296 && i+1 < len(codeUnits) && (int32(codeUnits[i+1])&64512) == 56320 {
+					charCode = 65536 + ((charCode & 1023) << 10) + (int32(codeUnits[i+1]) & 1023)
 					i++
 					bytesArray = append(bytesArray, (charCode>>18)|240)
 					bytesArray = append(bytesArray, ((charCode>>12)&63)|128)
@@ -95,14 +101,27 @@ func transformQuery(query string) []int32 {
 	return bytesArray
 }
 
-func calcHash(query string, windowTkk string) string {
+// parseTKK splits a TKK value of the form <index>.<key> into its two integers
+func parseTKK(tkk string) (int, int, error) {
 	// Split the tkk string on the '.' character
-	tkkSplited := strings.Split(windowTkk, ".")
+	tkkSplited := strings.Split(tkk, ".")
+	if len(tkkSplited) != 2 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidTKK, tkk)
+	}
 	// Convert the first part of the split tkk string to an integer
-	tkkIndex, _ := strconv.Atoi(tkkSplited[0])
+	tkkIndex, err := strconv.Atoi(tkkSplited[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidTKK, tkk)
+	}
 	// Convert the second part of the split tkk string to an integer
-	tkkKey, _ := strconv.Atoi(tkkSplited[1])
+	tkkKey, err := strconv.Atoi(tkkSplited[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidTKK, tkk)
+	}
+	return tkkIndex, tkkKey, nil
+}
 
+func calcHash(query string, tkkIndex, tkkKey int) string {
 	// Transform the query string into a sequence of integers
 	bytesArray := transformQuery(query)
 
@@ -120,8 +139,8 @@ func calcHash(query string, windowTkk string) string {
 
 	// XOR the encoding round with the tkk key
 	encondingRound ^= tkkKey
-	// If the encoding round is less than or equal to 0, perform a bitwise AND operation with 2147483647 and add 2147483648
-	if encondingRound <= 0 {
+	// If the encoding round is negative, perform a bitwise AND operation with 2147483647 and add 2147483648
+	if encondingRound < 0 {
 		encondingRound = (encondingRound & 2147483647) + 2147483648
 	}
 
@@ -131,9 +150,12 @@ func calcHash(query string, windowTkk string) string {
 	return strconv.Itoa(normalizedResult) + "." + strconv.Itoa(normalizedResult^tkkIndex)
 }
 
-func getToken(query string) string {
-	// Call the calcHash function with the input query and the constant googleTranslateTKK
-	// The googleTranslateTKK is likely a key or seed used in the hash calculation
+func getToken(query string, tkk string) (string, error) {
+	// The TKK is a key or seed used in the hash calculation, provided by a TKKProvider
+	tkkIndex, tkkKey, err := parseTKK(tkk)
+	if err != nil {
+		return "", err
+	}
 	// Return the result of the hash calculation
-	return calcHash(query, googleTranslateTKK)
+	return calcHash(query, tkkIndex, tkkKey), nil
 }
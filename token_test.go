@@ -0,0 +1,49 @@
+package gtranslate
+
+import (
+	"errors"
+	"testing"
+)
+
+// The expected tokens were computed with the JavaScript implementation of the translate web page
+func TestGetToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		tkk   string
+		want  string
+	}{
+		{"ascii", "hello", googleTranslateTKK, "576358.924801"},
+		{"ascii punctuation", "Hello, world!", googleTranslateTKK, "363462.220193"},
+		{"empty", "", googleTranslateTKK, "349507.230052"},
+		{"persian", "سلام دنیا", googleTranslateTKK, "139974.324897"},
+		{"cjk", "你好世界", googleTranslateTKK, "676120.820991"},
+		{"cyrillic", "Привет, мир", googleTranslateTKK, "280512.167975"},
+		{"emoji with modifier", "😀 emoji 👍🏽", googleTranslateTKK, "314881.137702"},
+		{"surrogate pair", "𝄞 clef", googleTranslateTKK, "564008.937167"},
+		{"other tkk", "hello", "406398.561267071", "352367.217873"},
+		{"other tkk latin", "héllo wörld", "406398.561267071", "823785.696983"},
+		{"zero tkk", "a", "0.0", "50242.50242"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getToken(tt.query, tt.tkk)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTokenInvalidTKK(t *testing.T) {
+	for _, tkk := range []string{"", "abc", "448487", "448487.", ".932609646", "1.2.3", "a.1"} {
+		t.Run(tkk, func(t *testing.T) {
+			if _, err := getToken("hello", tkk); !errors.Is(err, ErrInvalidTKK) {
+				t.Errorf("got %v, want ErrInvalidTKK", err)
+			}
+		})
+	}
+}
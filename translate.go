@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"golang.org/x/text/language"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
-	"time"
+	"unicode/utf8"
 )
 
 // Define constants for API paths and user agent
@@ -19,13 +22,11 @@ const (
 	userAgent               = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15"
 )
 
-// Define an HTTP client with a timeout
-var client = &http.Client{
-	Timeout: time.Second * 10, // Timeout after 10 seconds
-}
+// defaultClient is the Client used by the package-level functions
+var defaultClient = NewClient()
 
-// Function to prepare a URL for API request
-func prepareURL(apiPath string, data map[string]string) (*url.URL, error) {
+// Function to prepare a URL for API request, asking for the given response sections
+func prepareURL(apiPath string, data map[string]string, sections Section) (*url.URL, error) {
 	u, err := url.Parse(apiPath)
 	if err != nil {
 		return nil, fmt.Errorf("parse url: %w", err)
@@ -36,7 +37,7 @@ func prepareURL(apiPath string, data map[string]string) (*url.URL, error) {
 	for k, v := range data {
 		parameters.Add(k, v)
 	}
-	for _, v := range []string{"at", "bd", "ex", "ld", "md", "qca", "rw", "rm", "ss", "t"} {
+	for _, v := range sections.dtParameters() {
 		parameters.Add("dt", v)
 	}
 
@@ -44,12 +45,102 @@ func prepareURL(apiPath string, data map[string]string) (*url.URL, error) {
 	return u, nil
 }
 
-// Function to execute a HTTP request
-func doRequest(ctx context.Context, req *http.Request) ([]byte, error) {
+// Function to execute a HTTP request carrying the given number of characters,
+// throttled by the client's rate limiter and retrying transient failures according to its retry policy
+func (c *Client) doRequest(ctx context.Context, req *http.Request, characters int) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(ctx, characters); err != nil {
+				return nil, err
+			}
+		}
+
+		body, err := c.sendWithBreaker(ctx, req)
+		if err == nil || attempt >= c.retryPolicy.MaxAttempts || !isRetryable(err) {
+			return body, err
+		}
+
+		delay := c.retryPolicy.delay(attempt, err)
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(RetryAttempt{Attempt: attempt, Delay: delay, Err: err})
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		// The body of the previous attempt has been consumed, so it is recreated for the next one
+		if req.GetBody != nil {
+			newBody, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewind request body: %w", err)
+			}
+			req = req.Clone(ctx)
+			req.Body = newBody
+		}
+	}
+}
+
+// Function to execute a HTTP request signed with the tk token of the query.
+// When the API rejects the token, the TKK is refreshed once and the request is sent again.
+func (c *Client) doSignedRequest(ctx context.Context, query string, characters int, build func(token string) (*http.Request, error)) ([]byte, error) {
+	for refreshed := false; ; refreshed = true {
+		tkk, err := c.tkkProvider.TKK(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get tkk: %w", err)
+		}
+
+		token, err := getToken(query, tkk)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := build(token)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.doRequest(ctx, req, characters)
+		if err == nil || refreshed || !errors.Is(err, ErrBlocked) {
+			return body, err
+		}
+
+		invalidator, canInvalidate := c.tkkProvider.(interface{ Invalidate() })
+		if !canInvalidate {
+			return nil, err
+		}
+		invalidator.Invalidate()
+	}
+}
+
+// Function to execute a single attempt of a HTTP request, failing fast while the circuit of its endpoint is open
+func (c *Client) sendWithBreaker(ctx context.Context, req *http.Request) ([]byte, error) {
+	if c.breakers == nil {
+		return c.sendRequest(ctx, req)
+	}
+
+	breaker := c.breakers.get(requestEndpoint(req))
+	if err := breaker.allow(c.breakerStateChange); err != nil {
+		return nil, err
+	}
+
+	body, err := c.sendRequest(ctx, req)
+	switch {
+	case err == nil:
+		breaker.record(true, c.breakerStateChange)
+	case ctx.Err() == nil && isBreakerFailure(err):
+		breaker.record(false, c.breakerStateChange)
+	default:
+		breaker.release()
+	}
+	return body, err
+}
+
+// Function to execute a single attempt of a HTTP request
+func (c *Client) sendRequest(ctx context.Context, req *http.Request) ([]byte, error) {
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("User-Agent", c.userAgent)
 
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
 	}
@@ -61,7 +152,8 @@ func doRequest(ctx context.Context, req *http.Request) ([]byte, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("error in calling Google translate API")
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		return nil, newAPIError(resp, snippet)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -72,20 +164,60 @@ func doRequest(ctx context.Context, req *http.Request) ([]byte, error) {
 	return body, nil
 }
 
-// Translate Function to translate a single piece of content
+// Translate Function to translate a single piece of content using the default client
 func Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
-	sourceLanguageStr := "auto"
-	if !sourceLanguage.IsRoot() {
-		sourceLanguageStr = sourceLanguage.String()
+	return defaultClient.Translate(ctx, content, sourceLanguage, targetLanguage)
+}
+
+// TranslateBatch Function to translate a batch of content using the default client.
+// The languages are Google codes such as "fr" or "zh-TW"; an empty or "auto" source language is detected.
+// Use Client.TranslateBatch to pass language tags instead.
+func TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage string) ([]BatchResult, error) {
+	if len(contents) == 0 {
+		return []BatchResult{}, nil
+	}
+	from, err := defaultClient.requestLanguageTag(sourceLanguage)
+	if err != nil {
+		return nil, err
+	}
+	to, err := defaultClient.requestLanguageTag(targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	return defaultClient.TranslateBatch(ctx, contents, from, to)
+}
+
+// Translate translates a single piece of content.
+// A root source or target language falls back to the client's configured default,
+// and ErrNoTargetLanguage is returned when neither gives a target language.
+func (c *Client) Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	sourceLanguage, targetLanguage, err := c.resolveLanguages(sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
 	}
-	targetLanguageStr := "fa"
-	if !targetLanguage.IsRoot() {
-		targetLanguageStr = targetLanguage.String()
+
+	sourceLanguageStr, err := c.languageCode(sourceLanguage)
+	if err != nil {
+		return nil, err
+	}
+	targetLanguageStr, err := c.languageCode(targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validateLanguageCodes(ctx, sourceLanguageStr, targetLanguageStr); err != nil {
+		return nil, err
 	}
 
-	token := getToken(content)
+	if c.maxChunkLength > 0 && utf8.RuneCountInString(content) > c.maxChunkLength {
+		return c.translateChunks(ctx, content, sourceLanguageStr, targetLanguageStr)
+	}
+	return c.translateSingle(ctx, content, sourceLanguageStr, targetLanguageStr)
+}
 
-	// Prepare the data for the request
+// Function to translate content with a single request to the translation endpoint
+func (c *Client) translateSingle(ctx context.Context, content, sourceLanguageStr, targetLanguageStr string) (*TranslationResult, error) {
+	// Prepare the data for the request, the tk token is added when signing it
 	data := map[string]string{
 		"client": "gtx",
 		"sl":     sourceLanguageStr,
@@ -98,36 +230,106 @@ func Translate(ctx context.Context, content string, sourceLanguage, targetLangua
 		"tsel":   "0",
 		"kc":     "7",
 		"q":      content,
-		"tk":     token,
 	}
 
-	u, err := prepareURL(googleTranslateAPI, data)
+	body, err := c.doSignedRequest(ctx, content, utf8.RuneCountInString(content), func(token string) (*http.Request, error) {
+		data["tk"] = token
+		return c.newSingleRequest(c.translateURL, data)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	t, diagnostics, err := parseTranslationJSON(body, c.sections)
+	if err != nil {
+		return nil, fmt.Errorf("parse translation JSON: %w", err)
+	}
+	if err := c.checkSchema(diagnostics); err != nil {
+		return nil, fmt.Errorf("parse translation JSON: %w", err)
+	}
+	c.frequencyThresholds.classify(t)
+
+	t.SourceLanguage, err = c.languageTag(t.SourceLanguageCode)
+	if err != nil {
+		return nil, err
+	}
+	if t.SuggestedSourceLanguageCode != "" {
+		t.SuggestedSourceLanguage, err = c.languageTag(t.SuggestedSourceLanguageCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Function to create the request for the single translation endpoint.
+// The query is moved to a form-encoded POST body when the URL would exceed the client's maximum URL length,
+// all other parameters, including dt and tk, stay in the URL.
+func (c *Client) newSingleRequest(apiPath string, data map[string]string) (*http.Request, error) {
+	u, err := prepareURL(apiPath, data, c.sections)
+	if err != nil {
+		return nil, err
+	}
+	if c.maxURLLength < 0 || len(u.String()) <= c.maxURLLength {
+		req, err := http.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		return req, nil
+	}
+
+	urlData := make(map[string]string, len(data))
+	for k, v := range data {
+		if k != "q" {
+			urlData[k] = v
+		}
+	}
+	u, err = prepareURL(apiPath, urlData, c.sections)
+	if err != nil {
+		return nil, err
+	}
+
+	body := url.Values{"q": {data["q"]}}.Encode()
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	return req, nil
+}
 
-	body, err := doRequest(ctx, req)
+// TranslateBatch translates a batch of content and returns one result per input, in input order.
+// A root source or target language falls back to the client's configured default,
+// and ErrNoTargetLanguage is returned when neither gives a target language.
+// If some items cannot be recovered from the response, the remaining results are
+// still returned together with a *BatchError listing the failed indexes.
+func (c *Client) TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error) {
+	sourceLanguage, targetLanguage, err := c.resolveLanguages(sourceLanguage, targetLanguage)
 	if err != nil {
 		return nil, err
 	}
 
-	t, err := parseTranslationJSON(body)
+	from, err := c.languageCode(sourceLanguage)
 	if err != nil {
-		return nil, fmt.Errorf("parse translation JSON: %w", err)
+		return nil, err
+	}
+	to, err := c.languageCode(targetLanguage)
+	if err != nil {
+		return nil, err
 	}
 
-	return t, nil
+	if err := c.validateLanguageCodes(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	return c.translateBatch(ctx, contents, sourceLanguage, from, to)
 }
 
-// TranslateBatch Function to translate a batch of content
-func TranslateBatch(ctx context.Context, contents []string, from string, to string) ([]string, error) {
+// Function to translate a batch of content between the given Google language codes.
+// The source language is reported for the items whose source language is missing in the response.
+func (c *Client) translateBatch(ctx context.Context, contents []string, sourceLanguage language.Tag, from, to string) ([]BatchResult, error) {
 	preparedText := encodeForBatch(contents)
-	token := getToken(strings.Join(preparedText, ""))
 
 	data := map[string]string{
 		"anno":   "3",
@@ -136,33 +338,113 @@ func TranslateBatch(ctx context.Context, contents []string, from string, to stri
 		"format": "html",
 		"sl":     from,
 		"tl":     to,
-		"tk":     token,
 	}
 
-	u, err := prepareURL(googleTranslateBatchAPI, data)
+	respBody, err := c.doSignedRequest(ctx, strings.Join(preparedText, ""), countCharacters(contents), func(token string) (*http.Request, error) {
+		data["tk"] = token
+		// The batch endpoint only returns translations, so no sections are requested
+		u, err := prepareURL(c.batchURL, data, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		body := url.Values{"q": preparedText}.Encode()
+		req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+		return req, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	body := strings.Join(preparedText, "&q=")
-	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+	items, diagnostics, err := parseBatchJSON(respBody)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("parse batch JSON: %w", err)
+	}
+	if err := c.checkSchema(diagnostics); err != nil {
+		return nil, fmt.Errorf("parse batch JSON: %w", err)
 	}
 
-	respBody, err := doRequest(ctx, req)
-	if err != nil {
-		return nil, err
+	return c.assembleBatchResults(contents, items, sourceLanguage)
+}
+
+// assembleBatchResults maps the decoded response items back to the inputs by their batch index
+func (c *Client) assembleBatchResults(contents []string, items []batchItem, sourceLanguage language.Tag) ([]BatchResult, error) {
+	results := make([]BatchResult, len(contents))
+	found := make([]bool, len(contents))
+	for i, content := range contents {
+		results[i] = BatchResult{Index: i, Content: content, SourceLanguage: sourceLanguage}
 	}
 
-	return []string{string(respBody)}, nil
+	for _, item := range items {
+		for idx, translation := range decodeFromBatch(item.Translation) {
+			if idx < 0 || idx >= len(contents) {
+				continue
+			}
+			results[idx].Translation = translation
+			if item.SourceLanguage != "" {
+				tag, err := c.languageTag(item.SourceLanguage)
+				if err != nil {
+					return nil, err
+				}
+				results[idx].SourceLanguage = tag
+			}
+			found[idx] = true
+		}
+	}
+
+	var failed []int
+	for i, ok := range found {
+		if !ok {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BatchError{Indexes: failed}
+	}
+
+	return results, nil
 }
 
-// Function to prepare the content for batch translation
+// Function to prepare the content for batch translation.
+// The batch endpoint is called with format=html, so the content is escaped to keep
+// markup, ampersands and entities in the input from being interpreted as HTML.
 func encodeForBatch(textList []string) []string {
 	encodedText := make([]string, len(textList))
 	for i, text := range textList {
-		encodedText[i] = fmt.Sprintf("<pre><a i=\"%d\">%s</a></pre>", i, text)
+		encodedText[i] = fmt.Sprintf("<pre><a i=\"%d\">%s</a></pre>", i, html.EscapeString(text))
 	}
 	return encodedText
 }
+
+// batchAnchor matches the anchors produced by encodeForBatch in the translated output
+var batchAnchor = regexp.MustCompile(`(?s)<a i="?(\d+)"?>(.*?)</a>`)
+
+// Function to recover the translated content of each batch index from a translated payload.
+// Google may split one anchor into several when it reorders words, so consecutive anchors
+// with the same index are joined together with the text between them.
+// The recovered content is unescaped, reversing the escaping done by encodeForBatch.
+func decodeFromBatch(text string) map[int]string {
+	decoded := make(map[int]string)
+	lastIdx, lastEnd := -1, 0
+	for _, m := range batchAnchor.FindAllStringSubmatchIndex(text, -1) {
+		idx, err := strconv.Atoi(text[m[2]:m[3]])
+		if err != nil {
+			continue
+		}
+		content := text[m[4]:m[5]]
+		if idx == lastIdx {
+			decoded[idx] += text[lastEnd:m[0]] + content
+		} else {
+			decoded[idx] += content
+		}
+		lastIdx, lastEnd = idx, m[1]
+	}
+	for idx, content := range decoded {
+		decoded[idx] = html.UnescapeString(content)
+	}
+	return decoded
+}
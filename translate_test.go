@@ -0,0 +1,194 @@
+package gtranslate
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestBatchRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents []string
+	}{
+		{"plain", []string{"Hello", "Goodbye"}},
+		{"ampersand literal", []string{"Tom & Jerry", "&amp; stays &amp;"}},
+		{"markup", []string{"<b>bold</b> and <i>italic</i>", `<a i="7">fake anchor</a>`}},
+		{"query separator", []string{"a=1&q=2", "&q=&tl=de"}},
+		{"quotes and entities", []string{`"quoted" 'single'`, "&lt;not a tag&gt; &#39;"}},
+		{"multiline", []string{"first line\nsecond line", "\ttabbed  "}},
+		{"unicode", []string{"سلام دنیا", "日本語", "😀 emoji"}},
+		{"empty", []string{"", "not empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := decodeFromBatch(strings.Join(encodeForBatch(tt.contents), ""))
+			for i, content := range tt.contents {
+				if got, ok := decoded[i]; !ok || got != content {
+					t.Errorf("item %d: got %q (found %v), want %q", i, got, ok, content)
+				}
+			}
+			if len(decoded) != len(tt.contents) {
+				t.Errorf("got %d items, want %d", len(decoded), len(tt.contents))
+			}
+		})
+	}
+}
+
+func TestDecodeFromBatch(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[int]string
+	}{
+		{
+			name: "quoted and unquoted indexes",
+			text: `<pre><a i="0">Hallo</a></pre><pre><a i=1>Tschüss</a></pre>`,
+			want: map[int]string{0: "Hallo", 1: "Tschüss"},
+		},
+		{
+			name: "split anchor joined with the text between",
+			text: `<pre><a i="0">Das</a> rote <a i="0">Haus</a></pre><pre><a i="1">Ja</a></pre>`,
+			want: map[int]string{0: "Das rote Haus", 1: "Ja"},
+		},
+		{
+			name: "anchors of another index are not joined",
+			text: `<a i="0">eins</a> <a i="1">zwei</a> <a i="0">drei</a>`,
+			want: map[int]string{0: "einsdrei", 1: "zwei"},
+		},
+		{
+			name: "escaped ampersand and markup",
+			text: `<a i="0">Tom &amp;amp; Jerry</a><a i="1">&lt;b&gt;fett&lt;/b&gt;</a>`,
+			want: map[int]string{0: "Tom &amp; Jerry", 1: "<b>fett</b>"},
+		},
+		{
+			name: "query separator",
+			text: `<a i="0">a=1&amp;q=2</a>`,
+			want: map[int]string{0: "a=1&q=2"},
+		},
+		{
+			name: "newlines inside an anchor",
+			text: "<a i=\"0\">erste\nzweite</a>",
+			want: map[int]string{0: "erste\nzweite"},
+		},
+		{
+			name: "no anchors",
+			text: "Hallo",
+			want: map[int]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeFromBatch(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestLanguageTag(t *testing.T) {
+	tests := []struct {
+		code string
+		want language.Tag
+	}{
+		{"", language.Und},
+		{"auto", language.Und},
+		{"fr", language.French},
+		{"iw", language.Hebrew},
+		{"zh-TW", language.MustParse("zh-TW")},
+	}
+	client := NewClient()
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := client.requestLanguageTag(tt.code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	strict := client.With(WithStrictLanguages(true))
+	if _, err := strict.requestLanguageTag("not a code"); err == nil {
+		t.Error("got no error for an unknown code in strict mode")
+	}
+}
+
+func TestTranslateBatchEmpty(t *testing.T) {
+	// Empty input returns before any request or language lookup
+	results, err := TranslateBatch(context.Background(), nil, "auto", "not a code")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results == nil || len(results) != 0 {
+		t.Errorf("got %v, want an empty result", results)
+	}
+}
+
+func TestNewSingleRequest(t *testing.T) {
+	data := map[string]string{"client": "gtx", "sl": "en", "tl": "de", "q": "Hello world", "tk": "123.456"}
+	u, err := prepareURL("https://translate.example/translate_a/single", data, SectionAll)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	length := len(u.String())
+
+	tests := []struct {
+		name         string
+		maxURLLength int
+		wantMethod   string
+	}{
+		{"URL at the limit", length, "GET"},
+		{"URL above the limit", length - 1, "POST"},
+		{"no limit", -1, "GET"},
+		{"always post", 0, "POST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithMaxURLLength(tt.maxURLLength))
+			req, err := client.newSingleRequest("https://translate.example/translate_a/single", data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if req.Method != tt.wantMethod {
+				t.Fatalf("got method %s, want %s", req.Method, tt.wantMethod)
+			}
+
+			query := req.URL.Query()
+			if query.Get("tk") != "123.456" || !reflect.DeepEqual(query["dt"], SectionAll.dtParameters()) {
+				t.Errorf("got query %v, want tk and dt in the query string", query)
+			}
+			if tt.wantMethod == "GET" {
+				if query.Get("q") != "Hello world" {
+					t.Errorf("got q=%q in the query string, want %q", query.Get("q"), "Hello world")
+				}
+				return
+			}
+
+			if contentType := req.Header.Get("Content-Type"); contentType != "application/x-www-form-urlencoded;charset=UTF-8" {
+				t.Errorf("got Content-Type %q, want a URL-encoded form", contentType)
+			}
+			if _, isPresent := query["q"]; isPresent {
+				t.Errorf("got q=%q in the query string of a POST request", query.Get("q"))
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			form, err := url.ParseQuery(string(body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if form.Get("q") != "Hello world" {
+				t.Errorf("got q=%q in the body, want %q", form.Get("q"), "Hello world")
+			}
+		})
+	}
+}
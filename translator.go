@@ -0,0 +1,117 @@
+package gtranslate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/text/language"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Translator is implemented by every translation backend, so that callers can switch providers by configuration.
+// Client implements it with Google's unofficial endpoints. The constructors of the other backends accept
+// the options of NewClient, which configure their transport, retries and rate limiting.
+type Translator interface {
+	// Name returns a short identifier of the backend.
+	Name() string
+	// Translate translates a single piece of content. A root source language means automatic detection.
+	Translate(ctx context.Context, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error)
+	// TranslateBatch translates several pieces of content and returns one result per input, in input order.
+	TranslateBatch(ctx context.Context, contents []string, sourceLanguage, targetLanguage language.Tag) ([]BatchResult, error)
+	// Detect detects the language of the content.
+	Detect(ctx context.Context, content string) (*Detection, error)
+	// SupportedLanguages lists the supported languages, named in the display language when the backend allows it.
+	SupportedLanguages(ctx context.Context, displayLanguage language.Tag) (*Languages, error)
+}
+
+// Make sure the Google client implements the interface
+var _ Translator = (*Client)(nil)
+
+// WithProviderURL sets the base URL of an alternative backend such as DeepL, LibreTranslate,
+// Microsoft Translator or the Cloud Translation API, overriding its default endpoint
+func WithProviderURL(baseURL string) Option {
+	return func(c *Client) {
+		c.providerURL = baseURL
+	}
+}
+
+// newBackendClient creates the client of an alternative backend with the options of NewClient,
+// and returns it with the backend's base URL, without a trailing slash
+func newBackendClient(defaultURL string, opts []Option) (*Client, string) {
+	client := NewClient(opts...)
+	return client, strings.TrimSuffix(client.providerBaseURL(defaultURL), "/")
+}
+
+// providerBaseURL returns the configured provider URL, or the given default when none is set
+func (c *Client) providerBaseURL(defaultURL string) string {
+	if c.providerURL != "" {
+		return c.providerURL
+	}
+	return defaultURL
+}
+
+// Function to execute a JSON request against an alternative backend, decoding the response into out.
+// The request goes through the client's rate limiter and retry policy.
+func (c *Client) doJSON(ctx context.Context, method, apiURL string, header http.Header, in, out interface{}, characters int) error {
+	var body []byte
+	if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	req, err := http.NewRequest(method, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	respBody, err := c.doRequest(ctx, req, characters)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	return nil
+}
+
+// translateAsBatch translates a single piece of content as a batch of one item, for the backends
+// whose single translations are served by their batch endpoint
+func translateAsBatch(ctx context.Context, t Translator, content string, sourceLanguage, targetLanguage language.Tag) (*TranslationResult, error) {
+	results, err := t.TranslateBatch(ctx, []string{content}, sourceLanguage, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+	return resultFromTranslation(content, results[0].Translation, results[0].SourceLanguage), nil
+}
+
+// resultFromTranslation builds a TranslationResult from a backend that only returns the translated text
+func resultFromTranslation(content, translation string, sourceLanguage language.Tag) *TranslationResult {
+	return &TranslationResult{
+		Content:             content,
+		Translation:         translation,
+		SourceLanguage:      sourceLanguage,
+		TranslatedSentences: []Sentence{{Content: content, Translation: translation}},
+	}
+}
+
+// countCharacters returns the total number of characters of the contents
+func countCharacters(contents []string) int {
+	characters := 0
+	for _, content := range contents {
+		characters += utf8.RuneCountInString(content)
+	}
+	return characters
+}
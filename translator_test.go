@@ -0,0 +1,175 @@
+package gtranslate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// checkJSONBody decodes the JSON body of a request and compares it to want
+func checkJSONBody(t *testing.T, r *http.Request, body []byte, want string) {
+	t.Helper()
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("got Content-Type %q, want application/json", contentType)
+	}
+	var got, wantBody interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("request body %q is not JSON: %v", body, err)
+	}
+	json.Unmarshal([]byte(want), &wantBody)
+	if !reflect.DeepEqual(got, wantBody) {
+		t.Errorf("got request body %s, want %s", body, want)
+	}
+}
+
+func TestBackendRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		translator func(baseURL string) Translator
+		target     language.Tag
+		check      func(t *testing.T, r *http.Request, body []byte)
+		response   string
+		wantSource language.Tag
+	}{
+		{
+			name: "cloud v2",
+			translator: func(baseURL string) Translator {
+				return NewCloudV2Translator("api-key", WithProviderURL(baseURL))
+			},
+			target: language.MustParse("zh-Hant"),
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				if r.Method != "POST" || r.URL.Path != "/language/translate/v2" || r.URL.Query().Get("key") != "api-key" {
+					t.Errorf("got %s %s, want POST /language/translate/v2?key=api-key", r.Method, r.URL)
+				}
+				checkJSONBody(t, r, body, `{"q":["Hello","World"],"source":"en","target":"zh-TW","format":"text"}`)
+			},
+			response:   `{"data":{"translations":[{"translatedText":"你好"},{"translatedText":"世界"}]}}`,
+			wantSource: language.English,
+		},
+		{
+			name: "cloud v3",
+			translator: func(baseURL string) Translator {
+				return NewCloudV3Translator("my-project", "access-token", WithProviderURL(baseURL))
+			},
+			target: language.MustParse("zh-Hant"),
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				if r.Method != "POST" || r.URL.Path != "/v3/projects/my-project/locations/global:translateText" {
+					t.Errorf("got %s %s, want POST /v3/projects/my-project/locations/global:translateText", r.Method, r.URL)
+				}
+				if auth := r.Header.Get("Authorization"); auth != "Bearer access-token" {
+					t.Errorf("got Authorization %q", auth)
+				}
+				checkJSONBody(t, r, body, `{"contents":["Hello","World"],"mimeType":"text/plain","sourceLanguageCode":"en","targetLanguageCode":"zh-TW"}`)
+			},
+			response:   `{"translations":[{"translatedText":"你好"},{"translatedText":"世界"}]}`,
+			wantSource: language.English,
+		},
+		{
+			name: "deepl",
+			translator: func(baseURL string) Translator {
+				return NewDeepLTranslator("auth-key:fx", WithProviderURL(baseURL))
+			},
+			target: language.MustParse("zh-Hant"),
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				if r.Method != "POST" || r.URL.Path != "/translate" {
+					t.Errorf("got %s %s, want POST /translate", r.Method, r.URL)
+				}
+				if auth := r.Header.Get("Authorization"); auth != "DeepL-Auth-Key auth-key:fx" {
+					t.Errorf("got Authorization %q", auth)
+				}
+				checkJSONBody(t, r, body, `{"text":["Hello","World"],"source_lang":"EN","target_lang":"ZH-HANT"}`)
+			},
+			response:   `{"translations":[{"detected_source_language":"EN","text":"你好"},{"detected_source_language":"EN","text":"世界"}]}`,
+			wantSource: language.English,
+		},
+		{
+			name: "libretranslate",
+			translator: func(baseURL string) Translator {
+				return NewLibreTranslateTranslator(baseURL, "api-key")
+			},
+			target: language.MustParse("zh-Hant"),
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				if r.Method != "POST" || r.URL.Path != "/translate" {
+					t.Errorf("got %s %s, want POST /translate", r.Method, r.URL)
+				}
+				checkJSONBody(t, r, body, `{"q":["Hello","World"],"source":"en","target":"zh-Hant","format":"text","api_key":"api-key"}`)
+			},
+			response:   `{"translatedText":["你好","世界"]}`,
+			wantSource: language.English,
+		},
+		{
+			name: "microsoft",
+			translator: func(baseURL string) Translator {
+				return NewMicrosoftTranslator("subscription-key", "westeurope", WithProviderURL(baseURL))
+			},
+			target: language.MustParse("zh-TW"),
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				want := url.Values{"api-version": {"3.0"}, "from": {"en"}, "to": {"zh-Hant"}}
+				if r.Method != "POST" || r.URL.Path != "/translate" || !reflect.DeepEqual(r.URL.Query(), want) {
+					t.Errorf("got %s %s, want POST /translate?%s", r.Method, r.URL, want.Encode())
+				}
+				if key := r.Header.Get("Ocp-Apim-Subscription-Key"); key != "subscription-key" {
+					t.Errorf("got subscription key %q", key)
+				}
+				if region := r.Header.Get("Ocp-Apim-Subscription-Region"); region != "westeurope" {
+					t.Errorf("got subscription region %q", region)
+				}
+				checkJSONBody(t, r, body, `[{"Text":"Hello"},{"Text":"World"}]`)
+			},
+			response:   `[{"translations":[{"text":"你好","to":"zh-Hant"}]},{"translations":[{"text":"世界","to":"zh-Hant"}]}]`,
+			wantSource: language.English,
+		},
+		{
+			name: "google te",
+			translator: func(baseURL string) Translator {
+				return NewGoogleTETranslator(WithBatchURL(baseURL+"/translate_a/t"), WithTKKProvider(StaticTKK(googleTranslateTKK)), WithLanguageValidation(false))
+			},
+			target: language.MustParse("zh-Hant"),
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				query := r.URL.Query()
+				if r.Method != "POST" || r.URL.Path != "/translate_a/t" || query.Get("client") != "te" || query.Get("tk") == "" {
+					t.Errorf("got %s %s, want a signed POST to /translate_a/t with client=te", r.Method, r.URL)
+				}
+				if query.Get("sl") != "en" || query.Get("tl") != "zh-TW" {
+					t.Errorf("got sl=%q and tl=%q, want en and zh-TW", query.Get("sl"), query.Get("tl"))
+				}
+				form, err := url.ParseQuery(string(body))
+				if err != nil || !reflect.DeepEqual(form["q"], encodeForBatch([]string{"Hello", "World"})) {
+					t.Errorf("got form %q, want the encoded contents", body)
+				}
+			},
+			response:   `[["<pre><a i=\"0\">你好</a></pre>","en"],["<pre><a i=\"1\">世界</a></pre>","en"]]`,
+			wantSource: language.English,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				tt.check(t, r, body)
+				fmt.Fprint(w, tt.response)
+			}))
+			defer srv.Close()
+
+			results, err := tt.translator(srv.URL).TranslateBatch(context.Background(), []string{"Hello", "World"}, language.English, tt.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := []BatchResult{
+				{Index: 0, Content: "Hello", Translation: "你好", SourceLanguage: tt.wantSource},
+				{Index: 1, Content: "World", Translation: "世界", SourceLanguage: tt.wantSource},
+			}
+			if !reflect.DeepEqual(results, want) {
+				t.Errorf("got %+v, want %+v", results, want)
+			}
+		})
+	}
+}